@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"gogroceries/config"
+	"gogroceries/domain"
+	"gogroceries/repository/postgres"
+	"gogroceries/usecase"
+	"log"
+	"os"
+	"strings"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "  ADMIN_PASSWORD=<password> admin create -nama <nama> -email <email> -no_telp <no_telp>")
+	fmt.Fprintln(os.Stderr, "  admin create -nama <nama> -email <email> -no_telp <no_telp> -password-stdin < password.txt")
+	fmt.Fprintln(os.Stderr, "  admin promote -user <email|no_telp>")
+	os.Exit(2)
+}
+
+func main() {
+	ctx := context.Background()
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	if err := config.LoadConfig(); err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	cfg := config.AppConfig
+
+	db := postgres.ConnectDatabase(cfg)
+
+	userRepo := postgres.NewPostgresUserRepository(db)
+	userRoleRepo := postgres.NewPostgresUserRoleRepository(db)
+	adminUC := usecase.NewAdminUsecase(userRepo, userRoleRepo)
+
+	switch os.Args[1] {
+	case "create":
+		fs := flag.NewFlagSet("create", flag.ExitOnError)
+		req := domain.CreateAdminRequest{}
+		fs.StringVar(&req.Nama, "nama", "", "admin name")
+		fs.StringVar(&req.Email, "email", "", "admin email")
+		fs.StringVar(&req.NoTelp, "no_telp", "", "admin phone number")
+		passwordStdin := fs.Bool("password-stdin", false, "read the admin password from the first line of stdin")
+		fs.StringVar(&req.KataSandi, "password", "", "admin password (insecure: visible in the shell history and process list, prefer ADMIN_PASSWORD or -password-stdin)")
+		fs.Parse(os.Args[2:])
+
+		if err := readPassword(&req.KataSandi, *passwordStdin); err != nil {
+			log.Fatalf("Gagal membaca password: %v", err)
+		}
+
+		if req.Nama == "" || req.Email == "" || req.NoTelp == "" || len(req.KataSandi) < 6 {
+			fs.Usage()
+			os.Exit(2)
+		}
+
+		admin, err := adminUC.CreateAdmin(ctx, &req)
+		if err != nil {
+			log.Fatalf("Gagal membuat admin: %v", err)
+		}
+		log.Printf("Admin %s (ID %d) berhasil dibuat", admin.Email, admin.ID)
+
+	case "promote":
+		fs := flag.NewFlagSet("promote", flag.ExitOnError)
+		identifier := fs.String("user", "", "email or phone number of the user to promote")
+		fs.Parse(os.Args[2:])
+
+		if *identifier == "" {
+			fs.Usage()
+			os.Exit(2)
+		}
+
+		user, err := adminUC.PromoteAdmin(ctx, *identifier)
+		if err != nil {
+			log.Fatalf("Gagal promote admin: %v", err)
+		}
+		log.Printf("User %s (ID %d) sekarang admin", user.Email, user.ID)
+
+	default:
+		usage()
+	}
+}
+
+// readPassword takes the password from stdin when -password-stdin is set,
+// then from ADMIN_PASSWORD, and only falls back to the -password flag.
+func readPassword(password *string, fromStdin bool) error {
+	if fromStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return err
+		}
+		*password = strings.TrimRight(line, "\r\n")
+		return nil
+	}
+	if env := os.Getenv("ADMIN_PASSWORD"); env != "" {
+		*password = env
+		return nil
+	}
+	if *password != "" {
+		log.Println("Warning: -password is visible in the shell history and process list, use ADMIN_PASSWORD or -password-stdin")
+	}
+	return nil
+}
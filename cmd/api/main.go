@@ -1,46 +1,102 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"gogroceries/config"
 	"gogroceries/delivery/http"
 	"gogroceries/internal/helper"
-	"gogroceries/domain"
+	"gogroceries/internal/i18n"
+	"gogroceries/internal/logger"
+	"gogroceries/internal/metrics"
+	"gogroceries/internal/oidc"
+	"gogroceries/internal/tracing"
+	"gogroceries/internal/worker"
 	"gogroceries/repository/postgres"
 	"gogroceries/usecase"
 	"log"
+	"log/slog"
+	nethttp "net/http"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+const oauthStateCleanupInterval = 10 * time.Minute
+
 func Main() {
 	
 }
 
 func main() {
-	config.LoadConfig()
+	if err := config.LoadConfig(); err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
 	cfg := config.AppConfig
+	slog.SetDefault(logger.New(cfg.LogFormat, cfg.LogLevel))
+
+	if err := i18n.Check(); err != nil {
+		log.Fatalf("Invalid message catalogs: %v", err)
+	}
+	if err := helper.SetupValidator(); err != nil {
+		log.Fatalf("Failed to configure validator: %v", err)
+	}
+
+	shutdownTracing, err := tracing.Setup(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure tracing: %v", err)
+	}
 
 	db := postgres.ConnectDatabase(cfg)
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("Failed to get database handle: %v", err)
+	}
 
-	log.Println("Migrating database...")
-	errMigrate := db.AutoMigrate(
-		&domain.User{},
-		&domain.Toko{},
-		&domain.Alamat{},
-		&domain.Category{},
-		&domain.Produk{},
-		&domain.FotoProduk{},
-		&domain.Trx{},
-		&domain.DetailTrx{},
-		&domain.LogProduk{},
-	)
-	if errMigrate != nil {
-		log.Fatalf("Failed to migrate database: %v", errMigrate)
+	if err := metrics.RegisterDB(sqlDB, cfg.DBName); err != nil {
+		log.Fatalf("Failed to register database metrics: %v", err)
+	}
+
+	readinessChecks := map[string]http.ReadinessCheck{
+		"database": sqlDB.PingContext,
 	}
-	log.Println("Database migration successful!")
 
-	jwtAuth := helper.NewJWTHelper(cfg)
+	if cfg.DBAutoMigrate {
+		slog.Warn("DB_AUTO_MIGRATE is enabled, syncing schema from models")
+		if err := postgres.AutoMigrate(db); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+	} else {
+		migrator, err := postgres.NewMigrator(db)
+		if err != nil {
+			log.Fatalf("Failed to load migrations: %v", err)
+		}
+		pending, err := migrator.Pending(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to check migrations: %v", err)
+		}
+		if pending > 0 {
+			log.Fatalf("Database has %d pending migration(s), run: go run ./cmd/migrate up", pending)
+		}
+		readinessChecks["migrations"] = func(ctx context.Context) error {
+			pending, err := migrator.Pending(ctx)
+			if err != nil {
+				return err
+			}
+			if pending > 0 {
+				return fmt.Errorf("%d pending migration(s)", pending)
+			}
+			return nil
+		}
+	}
+
+	jwtAuth, err := helper.NewJWTHelper(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure JWT: %v", err)
+	}
 
 	userRepo := postgres.NewPostgresUserRepository(db)
 	tokoRepo := postgres.NewPostgresTokoRepository(db)
@@ -48,34 +104,112 @@ func main() {
 	categoryRepo := postgres.NewPostgresCategoryRepository(db)
 	trxRepo := postgres.NewPostgresTrxRepository(db)
 	alamatRepo := postgres.NewPostgresAlamatRepository(db)
+	userRoleRepo := postgres.NewPostgresUserRoleRepository(db)
+	tokoMemberRepo := postgres.NewPostgresTokoMemberRepository(db)
+	tokoInvitationRepo := postgres.NewPostgresTokoInvitationRepository(db)
+	apiKeyRepo := postgres.NewPostgresApiKeyRepository(db)
+	userIdentityRepo := postgres.NewPostgresUserIdentityRepository(db)
+	oauthStateRepo := postgres.NewPostgresOAuthStateRepository(db)
+	accountRepo := postgres.NewPostgresAccountRepository(db)
+	txManager := postgres.NewTxManager(db)
 
-	authUC := usecase.NewAuthUsecase(userRepo, tokoRepo, jwtAuth)
-	userUC := usecase.NewUserUsecase(userRepo)
-	tokoUC := usecase.NewTokoUsecase(tokoRepo)
-	produkUC := usecase.NewProdukUsecase(produkRepo, tokoRepo, categoryRepo)
-	categoryUC := usecase.NewCategoryUsecase(categoryRepo)
-	trxUC := usecase.NewTrxUsecase(trxRepo, produkRepo, alamatRepo, categoryRepo, tokoRepo)
-	alamatUC := usecase.NewAlamatUsecase(alamatRepo)
+	actorProvider := usecase.NewActorProvider(userRepo, userRoleRepo, tokoRepo, tokoMemberRepo)
 
-	engine := gin.Default()
+	authUC := usecase.NewAuthUsecase(userRepo, tokoRepo, userRoleRepo, tokoMemberRepo, jwtAuth, txManager)
+	userUC := usecase.NewUserUsecase(userRepo, accountRepo, userIdentityRepo, userRoleRepo)
+	tokoUC := usecase.NewTokoUsecase(tokoRepo, actorProvider)
+	produkUC := usecase.NewProdukUsecase(produkRepo, tokoRepo, categoryRepo, actorProvider, txManager)
+	categoryUC := usecase.NewCategoryUsecase(categoryRepo, txManager)
+	trxUC := usecase.NewTrxUsecase(trxRepo, produkRepo, alamatRepo, categoryRepo, tokoRepo, actorProvider, txManager)
+	alamatUC := usecase.NewAlamatUsecase(alamatRepo, actorProvider)
+	roleUC := usecase.NewRoleUsecase(userRepo, userRoleRepo)
+	adminUC := usecase.NewAdminUsecase(userRepo, userRoleRepo)
+	tokoMemberUC := usecase.NewTokoMemberUsecase(tokoMemberRepo, tokoInvitationRepo, userRepo, actorProvider, txManager)
+	apiKeyUC := usecase.NewApiKeyUsecase(apiKeyRepo, actorProvider)
+
+	oidcProviders, err := oidc.NewProviders(cfg.OIDCProviders)
+	if err != nil {
+		log.Fatalf("Failed to configure OIDC providers: %v", err)
+	}
+	oidcUC := usecase.NewOIDCUsecase(oidcProviders, oauthStateRepo, userIdentityRepo, userRepo, tokoRepo, userRoleRepo, tokoMemberRepo, jwtAuth, txManager)
+
+	if cfg.AppEnv != "development" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	engine := gin.New()
 
 	http.SetupRouter(
 		engine,
 		cfg,
 		authUC,
+		oidcUC,
 		userUC,
 		tokoUC,
 		produkUC,
 		categoryUC,
 		trxUC,
 		alamatUC,
+		roleUC,
+		adminUC,
+		tokoMemberUC,
+		apiKeyUC,
 		jwtAuth,
+		readinessChecks,
 	)
 
-	serverAddress := fmt.Sprintf(":%s", cfg.ServerPort)
-	log.Printf("Server berjalan di http://localhost%s", serverAddress)
-	err := engine.Run(serverAddress)
-	if err != nil {
-		log.Fatalf("Gagal menjalankan server: %v", err)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	workers := worker.NewRunner(
+		worker.Job{
+			Name:     "oauth-state-cleanup",
+			Interval: oauthStateCleanupInterval,
+			Run: func(ctx context.Context) error {
+				return oidcUC.CleanupExpiredStates(ctx)
+			},
+		},
+	)
+	workers.Start(ctx)
+
+	srv := &nethttp.Server{
+		Addr:              fmt.Sprintf(":%s", cfg.ServerPort),
+		Handler:           engine,
+		ReadHeaderTimeout: cfg.ServerReadHeaderTimeout,
+		ReadTimeout:       cfg.ServerReadTimeout,
+		WriteTimeout:      cfg.ServerWriteTimeout,
+		IdleTimeout:       cfg.ServerIdleTimeout,
 	}
-}
\ No newline at end of file
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("server started", "addr", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			log.Fatalf("Gagal menjalankan server: %v", err)
+		}
+	case <-ctx.Done():
+	}
+	stop()
+
+	slog.Info("shutting down, draining in-flight requests", "timeout", cfg.ServerShutdownTimeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ServerShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("server did not shut down cleanly", "error", err)
+	}
+	workers.Stop()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
+	}
+	if err := sqlDB.Close(); err != nil {
+		slog.Error("failed to close database", "error", err)
+	}
+	slog.Info("server stopped")
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"gogroceries/config"
+	"gogroceries/repository/postgres"
+	"log"
+	"os"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "  migrate up")
+	fmt.Fprintln(os.Stderr, "  migrate down [-steps <n>]")
+	fmt.Fprintln(os.Stderr, "  migrate status")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	if err := config.LoadConfig(); err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	cfg := config.AppConfig
+
+	db := postgres.ConnectDatabase(cfg)
+
+	migrator, err := postgres.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Gagal memuat migration: %v", err)
+	}
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			log.Printf("Applied %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Migration gagal: %v", err)
+		}
+		if len(applied) == 0 {
+			log.Println("Database sudah up to date")
+		}
+
+	case "down":
+		fs := flag.NewFlagSet("down", flag.ExitOnError)
+		steps := fs.Int("steps", 1, "number of migrations to roll back")
+		fs.Parse(os.Args[2:])
+
+		if *steps < 1 {
+			fs.Usage()
+			os.Exit(2)
+		}
+
+		reverted, err := migrator.Down(ctx, *steps)
+		for _, m := range reverted {
+			log.Printf("Rolled back %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Rollback gagal: %v", err)
+		}
+		if len(reverted) == 0 {
+			log.Println("Tidak ada migration untuk di-rollback")
+		}
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("Gagal membaca status migration: %v", err)
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-30s %s\n", s.Version, s.Name, applied)
+		}
+
+	default:
+		usage()
+	}
+}
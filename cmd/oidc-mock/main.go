@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"gogroceries/internal/oidcmock"
+	"log"
+	"net/http"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:9099", "listen address")
+	clientID := flag.String("client-id", "gogroceries", "accepted client id")
+	clientSecret := flag.String("client-secret", "gogroceries-secret", "accepted client secret")
+	subject := flag.String("sub", "mock-user-1", "subject of the logged in identity")
+	email := flag.String("email", "mock.user@example.com", "email of the logged in identity")
+	emailVerified := flag.Bool("email-verified", true, "whether the email is reported as verified")
+	name := flag.String("name", "Mock User", "name of the logged in identity")
+	flag.Parse()
+
+	server, err := oidcmock.New("http://"+*addr, *clientID, *clientSecret)
+	if err != nil {
+		log.Fatalf("Failed to create mock provider: %v", err)
+	}
+	server.SetIdentity(oidcmock.Identity{
+		Subject:       *subject,
+		Email:         *email,
+		EmailVerified: *emailVerified,
+		Name:          *name,
+	})
+
+	log.Printf("Mock OIDC provider listening on %s (issuer %s)", *addr, server.Issuer)
+	if err := http.ListenAndServe(*addr, server); err != nil {
+		log.Fatalf("Mock OIDC provider stopped: %v", err)
+	}
+}
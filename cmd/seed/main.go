@@ -0,0 +1,346 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"gogroceries/config"
+	"gogroceries/domain"
+	"gogroceries/internal/helper"
+	"gogroceries/repository/postgres"
+	"gogroceries/usecase"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	produkUploadDir = "./uploads/produk"
+	seedPassword    = "password123"
+)
+
+var categoryNames = []string{"Sayur", "Buah", "Daging", "Ikan", "Susu & Telur", "Bumbu Dapur", "Minuman", "Makanan Ringan"}
+
+var subCategoryNames = map[string][]string{
+	"Sayur": {"Sayur Daun", "Sayur Umbi"},
+}
+
+var produkNames = map[string][]string{
+	"Sayur":          {"Brokoli", "Kol", "Labu Siam"},
+	"Sayur Daun":     {"Bayam", "Kangkung", "Sawi Hijau", "Selada"},
+	"Sayur Umbi":     {"Wortel", "Kentang", "Lobak"},
+	"Buah":           {"Apel Fuji", "Pisang Cavendish", "Jeruk Medan", "Mangga Harum Manis", "Semangka", "Pepaya"},
+	"Daging":         {"Daging Sapi Has Dalam", "Ayam Potong", "Daging Kambing", "Sayap Ayam"},
+	"Ikan":           {"Ikan Kembung", "Udang Vaname", "Ikan Nila", "Cumi"},
+	"Susu & Telur":   {"Susu UHT", "Telur Ayam", "Telur Bebek", "Keju Cheddar"},
+	"Bumbu Dapur":    {"Bawang Merah", "Bawang Putih", "Cabai Rawit", "Jahe", "Kunyit"},
+	"Minuman":        {"Teh Melati", "Kopi Bubuk", "Air Mineral", "Sirup Jeruk"},
+	"Makanan Ringan": {"Keripik Singkong", "Kacang Atom", "Rengginang", "Emping"},
+}
+
+var firstNames = []string{"Budi", "Siti", "Agus", "Dewi", "Rudi", "Ayu", "Andi", "Rina", "Joko", "Maya", "Eko", "Lestari"}
+var lastNames = []string{"Santoso", "Wijaya", "Pratama", "Lestari", "Saputra", "Hidayat", "Nugroho", "Kusuma"}
+var cities = []string{"Jakarta", "Bandung", "Surabaya", "Yogyakarta", "Semarang", "Medan", "Makassar", "Denpasar"}
+var paymentMethods = []string{"transfer", "cod", "ewallet"}
+
+type seeder struct {
+	rng *rand.Rand
+	db  *gorm.DB
+
+	authUC     domain.AuthUsecase
+	adminUC    domain.AdminUsecase
+	tokoUC     domain.TokoUsecase
+	categoryUC domain.CategoryUsecase
+	produkUC   domain.ProdukUsecase
+	alamatUC   domain.AlamatUsecase
+	trxUC      domain.TrxUsecase
+}
+
+func main() {
+	seed := flag.Int64("seed", 1, "random seed; the same seed produces the same data")
+	reset := flag.Bool("reset", false, "truncate every table before seeding")
+	sellers := flag.Int("sellers", 3, "number of sellers (each with a toko)")
+	buyers := flag.Int("buyers", 5, "number of buyers")
+	orders := flag.Int("orders", 20, "number of historical transactions")
+	flag.Parse()
+	ctx := context.Background()
+
+	if err := config.LoadConfig(); err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	cfg := config.AppConfig
+
+	db := postgres.ConnectDatabase(cfg)
+
+	if *reset {
+		if cfg.AppEnv == "production" {
+			log.Fatal("Menolak -reset saat APP_ENV=production")
+		}
+		if err := postgres.TruncateAll(db); err != nil {
+			log.Fatalf("Gagal reset database: %v", err)
+		}
+		log.Println("Database di-reset")
+	}
+
+	jwtAuth, err := helper.NewJWTHelper(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure JWT: %v", err)
+	}
+
+	userRepo := postgres.NewPostgresUserRepository(db)
+	tokoRepo := postgres.NewPostgresTokoRepository(db)
+	produkRepo := postgres.NewPostgresProdukRepository(db)
+	categoryRepo := postgres.NewPostgresCategoryRepository(db)
+	trxRepo := postgres.NewPostgresTrxRepository(db)
+	alamatRepo := postgres.NewPostgresAlamatRepository(db)
+	userRoleRepo := postgres.NewPostgresUserRoleRepository(db)
+	tokoMemberRepo := postgres.NewPostgresTokoMemberRepository(db)
+	txManager := postgres.NewTxManager(db)
+
+	actorProvider := usecase.NewActorProvider(userRepo, userRoleRepo, tokoRepo, tokoMemberRepo)
+
+	s := &seeder{
+		rng:        rand.New(rand.NewSource(*seed)),
+		db:         db,
+		authUC:     usecase.NewAuthUsecase(userRepo, tokoRepo, userRoleRepo, tokoMemberRepo, jwtAuth, txManager),
+		adminUC:    usecase.NewAdminUsecase(userRepo, userRoleRepo),
+		tokoUC:     usecase.NewTokoUsecase(tokoRepo, actorProvider),
+		categoryUC: usecase.NewCategoryUsecase(categoryRepo, txManager),
+		produkUC:   usecase.NewProdukUsecase(produkRepo, tokoRepo, categoryRepo, actorProvider, txManager),
+		alamatUC:   usecase.NewAlamatUsecase(alamatRepo, actorProvider),
+		trxUC:      usecase.NewTrxUsecase(trxRepo, produkRepo, alamatRepo, categoryRepo, tokoRepo, actorProvider, txManager),
+	}
+
+	if err := s.run(ctx, *sellers, *buyers, *orders); err != nil {
+		log.Fatalf("Seeding gagal: %v", err)
+	}
+}
+
+func (s *seeder) run(ctx context.Context, sellerCount, buyerCount, orderCount int) error {
+	admin, err := s.adminUC.CreateAdmin(ctx, &domain.CreateAdminRequest{
+		Nama:      "Admin GoGroceries",
+		Email:     "admin@gogroceries.test",
+		NoTelp:    "080000000000",
+		KataSandi: seedPassword,
+	})
+	if err != nil {
+		return fmt.Errorf("admin: %w", err)
+	}
+	log.Printf("Admin: %s / %s", admin.NoTelp, seedPassword)
+
+	categories := make([]*domain.Category, 0, len(categoryNames))
+	for i, name := range categoryNames {
+		category, err := s.categoryUC.CreateCategory(ctx, &domain.CreateCategoryRequest{NamaCategory: name, SortOrder: i})
+		if err != nil {
+			return fmt.Errorf("category %s: %w", name, err)
+		}
+		categories = append(categories, category)
+
+		for j, childName := range subCategoryNames[name] {
+			child, err := s.categoryUC.CreateCategory(ctx, &domain.CreateCategoryRequest{NamaCategory: childName, ParentID: &category.ID, SortOrder: j})
+			if err != nil {
+				return fmt.Errorf("category %s: %w", childName, err)
+			}
+			categories = append(categories, child)
+		}
+	}
+	log.Printf("%d categories", len(categories))
+
+	if err := os.MkdirAll(produkUploadDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	type sellerSeed struct {
+		id      uint
+		tokoID  uint
+		produks []*domain.Produk
+	}
+	var sellers []*sellerSeed
+	produkCount := 0
+	usedNames := map[string]bool{}
+	for i := 1; i <= sellerCount; i++ {
+		user, err := s.register(ctx, "seller", i)
+		if err != nil {
+			return err
+		}
+
+		toko, err := s.tokoUC.GetMyToko(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("toko seller %d: %w", i, err)
+		}
+		namaToko := fmt.Sprintf("Toko %s %s", lastNames[s.rng.Intn(len(lastNames))], cities[s.rng.Intn(len(cities))])
+		if _, err := s.tokoUC.UpdateToko(ctx, toko.ID, &domain.UpdateTokoRequest{NamaToko: namaToko}, user.ID); err != nil {
+			return fmt.Errorf("toko seller %d: %w", i, err)
+		}
+		seller := &sellerSeed{id: user.ID, tokoID: toko.ID}
+		sellers = append(sellers, seller)
+
+		for j := 0; j < 4+s.rng.Intn(5); j++ {
+			category := categories[s.rng.Intn(len(categories))]
+			names := produkNames[category.NamaCategory]
+			nama := names[s.rng.Intn(len(names))]
+			if usedNames[nama] {
+				nama = fmt.Sprintf("%s %s", nama, namaToko)
+			}
+			if usedNames[nama] {
+				continue
+			}
+			usedNames[nama] = true
+
+			produkCount++
+			photo, err := s.writePhoto(produkCount)
+			if err != nil {
+				return err
+			}
+			hargaReseller := (5 + s.rng.Intn(96)) * 1000
+			produk, err := s.produkUC.CreateProduk(ctx, &domain.CreateProdukRequest{
+				NamaProduk:    nama,
+				IdCategory:    category.ID,
+				HargaReseller: hargaReseller,
+				HargaKonsumen: hargaReseller + (1+s.rng.Intn(10))*500,
+				Stok:          20 + s.rng.Intn(181),
+				Deskripsi:     fmt.Sprintf("%s segar dari %s.", nama, namaToko),
+				Photos:        []string{photo},
+			}, seller.id)
+			if err != nil {
+				return fmt.Errorf("produk %s: %w", nama, err)
+			}
+			seller.produks = append(seller.produks, produk)
+		}
+	}
+	log.Printf("%d sellers, %d produk", len(sellers), produkCount)
+
+	type buyer struct {
+		id       uint
+		alamatID uint
+	}
+	var buyersSeeded []buyer
+	for i := 1; i <= buyerCount; i++ {
+		user, err := s.register(ctx, "buyer", i)
+		if err != nil {
+			return err
+		}
+		alamat, err := s.alamatUC.CreateAlamat(ctx, &domain.CreateAlamatRequest{
+			JudulAlamat:  "Rumah",
+			NamaPenerima: user.Nama,
+			NoTelp:       user.NoTelp,
+			DetailAlamat: fmt.Sprintf("Jl. Merdeka No. %d, %s", 1+s.rng.Intn(200), cities[s.rng.Intn(len(cities))]),
+		}, user.ID)
+		if err != nil {
+			return fmt.Errorf("alamat buyer %d: %w", i, err)
+		}
+		buyersSeeded = append(buyersSeeded, buyer{id: user.ID, alamatID: alamat.ID})
+	}
+	log.Printf("%d buyers", len(buyersSeeded))
+
+	if produkCount == 0 || len(buyersSeeded) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	created := 0
+	for i := 0; i < orderCount; i++ {
+		b := buyersSeeded[s.rng.Intn(len(buyersSeeded))]
+		seller := sellers[s.rng.Intn(len(sellers))]
+		if len(seller.produks) == 0 {
+			continue
+		}
+
+		req := &domain.CreateTransaksiRequest{
+			IdAlamatKirim: b.alamatID,
+			MethodBayar:   paymentMethods[s.rng.Intn(len(paymentMethods))],
+		}
+		picked := map[uint]bool{}
+		for j := 0; j < 1+s.rng.Intn(3); j++ {
+			p := seller.produks[s.rng.Intn(len(seller.produks))]
+			if picked[p.ID] {
+				continue
+			}
+			picked[p.ID] = true
+			req.DetailTrx = append(req.DetailTrx, domain.CreateDetailTrxRequest{IdProduk: p.ID, Kuantitas: 1 + s.rng.Intn(3)})
+		}
+
+		trx, err := s.trxUC.CreateTransaksi(ctx, req, b.id)
+		if errors.Is(err, domain.ErrInsufficientStock) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("transaksi %d: %w", i+1, err)
+		}
+
+		if err := s.advanceStatus(ctx, trx, seller.tokoID, seller.id); err != nil {
+			return fmt.Errorf("status transaksi %d: %w", i+1, err)
+		}
+
+		createdAt := now.AddDate(0, 0, -s.rng.Intn(90)).Add(-time.Duration(s.rng.Intn(24*60)) * time.Minute)
+		if err := s.db.Model(&domain.Trx{}).Where("id = ?", trx.ID).
+			UpdateColumns(map[string]interface{}{"created_at": createdAt, "updated_at": createdAt}).Error; err != nil {
+			return err
+		}
+		created++
+	}
+	log.Printf("%d transaksi", created)
+	log.Printf("Semua user seed memakai kata sandi %q", seedPassword)
+
+	return nil
+}
+
+func (s *seeder) register(ctx context.Context, kind string, i int) (*domain.User, error) {
+	nama := fmt.Sprintf("%s %s", firstNames[s.rng.Intn(len(firstNames))], lastNames[s.rng.Intn(len(lastNames))])
+	noTelp := fmt.Sprintf("0812%04d%04d", map[string]int{"seller": 1, "buyer": 2}[kind], i)
+	user, err := s.authUC.Register(ctx, &domain.RegisterRequest{
+		Nama:      nama,
+		KataSandi: seedPassword,
+		NoTelp:    noTelp,
+		Email:     fmt.Sprintf("%s%d@gogroceries.test", kind, i),
+		IdKota:    cities[s.rng.Intn(len(cities))],
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s %d: %w", kind, i, err)
+	}
+	return user, nil
+}
+
+// advanceStatus walks the order through a random prefix of its lifecycle.
+func (s *seeder) advanceStatus(ctx context.Context, trx *domain.Trx, tokoID, sellerID uint) error {
+	status := trx.Status
+	for {
+		next := domain.TrxStatusTransitions[status]
+		if len(next) == 0 || s.rng.Intn(4) == 0 {
+			return nil
+		}
+		status = next[s.rng.Intn(len(next))]
+		if _, err := s.trxUC.UpdateStatusTransaksiToko(ctx, trx.ID, tokoID, &domain.UpdateTrxStatusRequest{Status: status}, sellerID); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *seeder) writePhoto(n int) (string, error) {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	fill := color.RGBA{R: uint8(s.rng.Intn(256)), G: uint8(s.rng.Intn(256)), B: uint8(s.rng.Intn(256)), A: 255}
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			img.Set(x, y, fill)
+		}
+	}
+
+	name := fmt.Sprintf("seed-%03d.png", n)
+	f, err := os.Create(filepath.Join(produkUploadDir, name))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		return "", err
+	}
+	return name, nil
+}
@@ -1,55 +1,191 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"log"
 	"os"
-	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	DBHost     string
-	DBPort     string
-	DBUser     string
-	DBPassword string
-	DBName     string
-	JWTSecret  string
-	ServerPort string
+	AppEnv    string
+	LogFormat string
+	LogLevel  string
+
+	DBHost            string
+	DBPort            string
+	DBUser            string
+	DBPassword        string
+	DBName            string
+	DBSSLMode         string
+	DBTimeZone        string
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+	DBConnMaxLifetime time.Duration
+	DBConnMaxIdleTime time.Duration
+	DBQueryTimeout    time.Duration
+	DBAutoMigrate     bool
+
+	JWTSecret         string
+	JWTAlgorithm      string
+	JWTKeyID          string
+	JWTPrivateKeyFile string
+	JWTVerifyKeys     string
+	JWTAccessTTL      time.Duration
+	JWTLeeway         time.Duration
+
+	ServerPort              string
+	ServerReadHeaderTimeout time.Duration
+	ServerReadTimeout       time.Duration
+	ServerWriteTimeout      time.Duration
+	ServerIdleTimeout       time.Duration
+	ServerShutdownTimeout   time.Duration
+
+	UploadMaxSize int64
+
+	MetricsToken string
+
+	TracingExporter    string
+	TracingFile        string
+	TracingSampleRatio float64
+
+	CORSAllowedOrigins   []string
+	CORSAllowedMethods   []string
+	CORSAllowedHeaders   []string
+	CORSAllowCredentials bool
+	CORSMaxAge           time.Duration
+
+	OIDCProviders []OIDCProviderConfig
+}
+
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
 }
 
 var AppConfig Config
 
-func LoadConfig() {
-	err := godotenv.Load()
-	if err != nil {
-		panic("Failed to load .env")
+// AllowsDefaultSecrets reports whether built-in secrets such as
+// JWT_SECRET=secret are acceptable. An unset APP_ENV means production, so a
+// forgotten variable fails closed.
+func (cfg Config) AllowsDefaultSecrets() bool {
+	return cfg.AppEnv == "development" || cfg.AppEnv == "test"
+}
+
+// LoadConfig reads the configuration and stores it in AppConfig. Values come
+// from, in order of precedence: environment variables, an optional .env file,
+// the YAML file of the active profile (config/<APP_ENV>.yaml, or CONFIG_FILE)
+// and finally the built-in defaults. Every invalid value is reported at once.
+func LoadConfig() error {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to load .env: %w", err)
 	}
 
-	AppConfig = Config{
-		DBHost:     getEnv("DB_HOST", "localhost"),
-		DBPort:     getEnv("DB_PORT", "5432"),
-		DBUser:     getEnv("DB_USER", "postgres"),
-		DBPassword: getEnv("DB_PASSWORD", ""),
-		DBName:     getEnv("DB_NAME", "gogroceries"),
-		JWTSecret:  getEnv("JWT_SECRET", "secret"),
-		ServerPort: getEnv("SERVER_PORT", "8080"),
+	appEnv := getEnv("APP_ENV", "production")
+	src := &source{}
+
+	path := getEnv("CONFIG_FILE", "")
+	explicit := path != ""
+	if !explicit {
+		path = fmt.Sprintf("config/%s.yaml", appEnv)
+	}
+	if err := src.loadFile(path); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) || explicit {
+			return fmt.Errorf("failed to load config file %s: %w", path, err)
+		}
+	} else {
+		log.Printf("Config file %s loaded", path)
 	}
 
-	log.Println("Config loaded")
+	cfg := Config{
+		AppEnv:    appEnv,
+		LogFormat: src.String("LOG_FORMAT", "json"),
+		LogLevel:  src.String("LOG_LEVEL", "info"),
+
+		DBHost:            src.String("DB_HOST", "localhost"),
+		DBPort:            src.String("DB_PORT", "5432"),
+		DBUser:            src.String("DB_USER", "postgres"),
+		DBPassword:        src.String("DB_PASSWORD", ""),
+		DBName:            src.String("DB_NAME", "gogroceries"),
+		DBSSLMode:         src.String("DB_SSLMODE", "require"),
+		DBTimeZone:        src.String("DB_TIMEZONE", "Asia/Jakarta"),
+		DBMaxOpenConns:    src.Int("DB_MAX_OPEN_CONNS", 25),
+		DBMaxIdleConns:    src.Int("DB_MAX_IDLE_CONNS", 5),
+		DBConnMaxLifetime: src.Duration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+		DBConnMaxIdleTime: src.Duration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+		DBQueryTimeout:    src.Duration("DB_QUERY_TIMEOUT", 10*time.Second),
+		DBAutoMigrate:     src.Bool("DB_AUTO_MIGRATE", false),
+
+		JWTSecret:         src.String("JWT_SECRET", "secret"),
+		JWTAlgorithm:      src.String("JWT_ALGORITHM", "HS256"),
+		JWTKeyID:          src.String("JWT_KEY_ID", ""),
+		JWTPrivateKeyFile: src.String("JWT_PRIVATE_KEY_FILE", ""),
+		JWTVerifyKeys:     src.String("JWT_VERIFY_KEYS", ""),
+		JWTAccessTTL:      src.Duration("JWT_ACCESS_TTL", 24*time.Hour),
+		JWTLeeway:         src.Duration("JWT_LEEWAY", 30*time.Second),
+
+		ServerPort:              src.String("SERVER_PORT", "8080"),
+		ServerReadHeaderTimeout: src.Duration("SERVER_READ_HEADER_TIMEOUT", 5*time.Second),
+		ServerReadTimeout:       src.Duration("SERVER_READ_TIMEOUT", 30*time.Second),
+		ServerWriteTimeout:      src.Duration("SERVER_WRITE_TIMEOUT", 30*time.Second),
+		ServerIdleTimeout:       src.Duration("SERVER_IDLE_TIMEOUT", 120*time.Second),
+		ServerShutdownTimeout:   src.Duration("SERVER_SHUTDOWN_TIMEOUT", 15*time.Second),
+
+		UploadMaxSize: int64(src.Int("UPLOAD_MAX_SIZE_MB", 10)) << 20,
+
+		MetricsToken: src.String("METRICS_TOKEN", ""),
+
+		TracingExporter:    src.String("TRACING_EXPORTER", "none"),
+		TracingFile:        src.String("TRACING_FILE", "traces.jsonl"),
+		TracingSampleRatio: src.Float("TRACING_SAMPLE_RATIO", 1),
+
+		CORSAllowedOrigins:   src.List("CORS_ALLOWED_ORIGINS", nil),
+		CORSAllowedMethods:   src.List("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
+		CORSAllowedHeaders:   src.List("CORS_ALLOWED_HEADERS", []string{"Authorization", "Content-Type", "Accept-Language", "X-API-Key", "X-Request-ID", "traceparent", "tracestate"}),
+		CORSAllowCredentials: src.Bool("CORS_ALLOW_CREDENTIALS", false),
+		CORSMaxAge:           src.Duration("CORS_MAX_AGE", 12*time.Hour),
+
+		OIDCProviders: loadOIDCProviders(src),
+	}
+
+	if err := cfg.Validate(src.problems); err != nil {
+		return err
+	}
+
+	AppConfig = cfg
+	log.Printf("Config loaded (profile %s)", cfg.AppEnv)
+	return nil
 }
 
-func getEnv(key string, fallback string) string {
-	if value, ok := os.LookupEnv(key); ok {
-		return value
+// OIDC_PROVIDERS lists provider names; each one is configured through
+// OIDC_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET and _REDIRECT_URL.
+func loadOIDCProviders(src *source) []OIDCProviderConfig {
+	var providers []OIDCProviderConfig
+	for _, name := range src.List("OIDC_PROVIDERS", nil) {
+		name = strings.ToLower(name)
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		providers = append(providers, OIDCProviderConfig{
+			Name:         name,
+			Issuer:       src.String(prefix+"ISSUER", ""),
+			ClientID:     src.String(prefix+"CLIENT_ID", ""),
+			ClientSecret: src.String(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  src.String(prefix+"REDIRECT_URL", ""),
+		})
 	}
-	return fallback
+	return providers
 }
 
-func getEnvAsInt(key string, fallback int) int {
-	valueStr := getEnv(key, "")
-	if value, err := strconv.Atoi(valueStr); err == nil {
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return fallback
-}
\ No newline at end of file
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// source looks a key up in the environment first and then in the profile
+// file. Values that fail to parse are collected in problems so they can be
+// reported together with the validation errors.
+type source struct {
+	file     map[string]string
+	problems []string
+}
+
+// loadFile reads a YAML profile. Nested keys are joined with "_" and upper
+// cased, so `db: {max_open_conns: 10}` sets DB_MAX_OPEN_CONNS; lists are
+// joined with ",".
+func (s *source) loadFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return err
+	}
+	s.file = map[string]string{}
+	flatten("", doc, s.file)
+	return nil
+}
+
+func flatten(prefix string, value interface{}, out map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			name := strings.ToUpper(key)
+			if prefix != "" {
+				name = prefix + "_" + name
+			}
+			flatten(name, child, out)
+		}
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		out[prefix] = strings.Join(items, ",")
+	case nil:
+		out[prefix] = ""
+	default:
+		out[prefix] = fmt.Sprint(v)
+	}
+}
+
+// lookup treats empty values as unset, so the blank lines of .env.example
+// fall through to the profile file and the defaults.
+func (s *source) lookup(key string) (string, bool) {
+	if value := os.Getenv(key); value != "" {
+		return value, true
+	}
+	value, ok := s.file[key]
+	return value, ok && value != ""
+}
+
+func (s *source) String(key, fallback string) string {
+	if value, ok := s.lookup(key); ok {
+		return value
+	}
+	return fallback
+}
+
+func (s *source) Int(key string, fallback int) int {
+	valueStr, ok := s.lookup(key)
+	if !ok {
+		return fallback
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		s.problems = append(s.problems, fmt.Sprintf("%s: %q is not an integer", key, valueStr))
+		return fallback
+	}
+	return value
+}
+
+func (s *source) Float(key string, fallback float64) float64 {
+	valueStr, ok := s.lookup(key)
+	if !ok {
+		return fallback
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		s.problems = append(s.problems, fmt.Sprintf("%s: %q is not a number", key, valueStr))
+		return fallback
+	}
+	return value
+}
+
+func (s *source) Bool(key string, fallback bool) bool {
+	valueStr, ok := s.lookup(key)
+	if !ok {
+		return fallback
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		s.problems = append(s.problems, fmt.Sprintf("%s: %q is not a boolean", key, valueStr))
+		return fallback
+	}
+	return value
+}
+
+func (s *source) Duration(key string, fallback time.Duration) time.Duration {
+	valueStr, ok := s.lookup(key)
+	if !ok {
+		return fallback
+	}
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		s.problems = append(s.problems, fmt.Sprintf("%s: %q is not a duration (e.g. 30s, 15m, 24h)", key, valueStr))
+		return fallback
+	}
+	return value
+}
+
+func (s *source) List(key string, fallback []string) []string {
+	valueStr, ok := s.lookup(key)
+	if !ok {
+		return fallback
+	}
+	var items []string
+	for _, item := range strings.Split(valueStr, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	appEnvs       = []string{"development", "test", "staging", "production"}
+	dbSSLModes    = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	jwtAlgorithms = []string{"HS256", "RS256", "EDDSA"}
+	logFormats    = []string{"json", "text"}
+	logLevels     = []string{"debug", "info", "warn", "error"}
+	tracingExps   = []string{"none", "stdout", "file"}
+)
+
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Validate checks every field and returns a *ValidationError listing all
+// problems, including the parse errors passed in by the loader.
+func (cfg Config) Validate(problems []string) error {
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if !contains(appEnvs, cfg.AppEnv) {
+		add("APP_ENV: %q must be one of %s", cfg.AppEnv, strings.Join(appEnvs, ", "))
+	}
+	if !contains(logFormats, cfg.LogFormat) {
+		add("LOG_FORMAT: %q must be one of %s", cfg.LogFormat, strings.Join(logFormats, ", "))
+	}
+	if !contains(logLevels, cfg.LogLevel) {
+		add("LOG_LEVEL: %q must be one of %s", cfg.LogLevel, strings.Join(logLevels, ", "))
+	}
+
+	for key, value := range map[string]string{"DB_HOST": cfg.DBHost, "DB_USER": cfg.DBUser, "DB_NAME": cfg.DBName} {
+		if value == "" {
+			add("%s: is required", key)
+		}
+	}
+	if !validPort(cfg.DBPort) {
+		add("DB_PORT: %q is not a valid port", cfg.DBPort)
+	}
+	if !contains(dbSSLModes, cfg.DBSSLMode) {
+		add("DB_SSLMODE: %q must be one of %s", cfg.DBSSLMode, strings.Join(dbSSLModes, ", "))
+	}
+	if cfg.AppEnv == "production" && cfg.DBSSLMode == "disable" {
+		add("DB_SSLMODE: disable is not allowed in production")
+	}
+	if _, err := time.LoadLocation(cfg.DBTimeZone); err != nil {
+		add("DB_TIMEZONE: %q is not a known time zone", cfg.DBTimeZone)
+	}
+	if cfg.DBMaxOpenConns < 1 {
+		add("DB_MAX_OPEN_CONNS: must be at least 1")
+	}
+	if cfg.DBMaxIdleConns < 0 || cfg.DBMaxIdleConns > cfg.DBMaxOpenConns {
+		add("DB_MAX_IDLE_CONNS: must be between 0 and DB_MAX_OPEN_CONNS (%d)", cfg.DBMaxOpenConns)
+	}
+	if cfg.DBConnMaxLifetime < 0 {
+		add("DB_CONN_MAX_LIFETIME: must not be negative")
+	}
+	if cfg.DBConnMaxIdleTime < 0 {
+		add("DB_CONN_MAX_IDLE_TIME: must not be negative")
+	}
+	if cfg.DBQueryTimeout <= 0 {
+		add("DB_QUERY_TIMEOUT: must be positive")
+	}
+
+	algorithm := strings.ToUpper(cfg.JWTAlgorithm)
+	if !contains(jwtAlgorithms, algorithm) {
+		add("JWT_ALGORITHM: %q must be one of HS256, RS256, EdDSA", cfg.JWTAlgorithm)
+	} else if algorithm == "HS256" {
+		if cfg.JWTSecret == "" {
+			add("JWT_SECRET: is required for HS256")
+		} else if cfg.JWTSecret == "secret" && !cfg.AllowsDefaultSecrets() {
+			add("JWT_SECRET: must be changed from the default outside development and test")
+		}
+		if cfg.JWTVerifyKeys != "" {
+			add("JWT_VERIFY_KEYS: is only supported with RS256 and EdDSA")
+		}
+	} else if cfg.JWTPrivateKeyFile == "" {
+		add("JWT_PRIVATE_KEY_FILE: is required for %s", cfg.JWTAlgorithm)
+	}
+	if cfg.JWTAccessTTL <= 0 {
+		add("JWT_ACCESS_TTL: must be positive")
+	}
+	if cfg.JWTLeeway < 0 || cfg.JWTLeeway > 5*time.Minute {
+		add("JWT_LEEWAY: must be between 0 and 5m")
+	}
+
+	if cfg.MetricsToken == "" && cfg.AppEnv != "development" {
+		add("METRICS_TOKEN: is required outside development")
+	}
+
+	if !validPort(cfg.ServerPort) {
+		add("SERVER_PORT: %q is not a valid port", cfg.ServerPort)
+	}
+	for key, value := range map[string]time.Duration{
+		"SERVER_READ_HEADER_TIMEOUT": cfg.ServerReadHeaderTimeout,
+		"SERVER_READ_TIMEOUT":        cfg.ServerReadTimeout,
+		"SERVER_WRITE_TIMEOUT":       cfg.ServerWriteTimeout,
+		"SERVER_IDLE_TIMEOUT":        cfg.ServerIdleTimeout,
+		"SERVER_SHUTDOWN_TIMEOUT":    cfg.ServerShutdownTimeout,
+	} {
+		if value <= 0 {
+			add("%s: must be positive", key)
+		}
+	}
+
+	if cfg.UploadMaxSize <= 0 || cfg.UploadMaxSize > 100<<20 {
+		add("UPLOAD_MAX_SIZE_MB: must be between 1 and 100")
+	}
+
+	for _, origin := range cfg.CORSAllowedOrigins {
+		if origin == "*" {
+			if cfg.CORSAllowCredentials {
+				add("CORS_ALLOWED_ORIGINS: \"*\" cannot be combined with CORS_ALLOW_CREDENTIALS")
+			}
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			add("CORS_ALLOWED_ORIGINS: %q is not an origin like https://shop.example.com", origin)
+		}
+	}
+	if len(cfg.CORSAllowedOrigins) > 0 && len(cfg.CORSAllowedMethods) == 0 {
+		add("CORS_ALLOWED_METHODS: must not be empty when CORS is enabled")
+	}
+	if cfg.CORSMaxAge < 0 {
+		add("CORS_MAX_AGE: must not be negative")
+	}
+
+	if !contains(tracingExps, cfg.TracingExporter) {
+		add("TRACING_EXPORTER: %q must be one of %s", cfg.TracingExporter, strings.Join(tracingExps, ", "))
+	}
+	if cfg.TracingExporter == "file" && cfg.TracingFile == "" {
+		add("TRACING_FILE: is required when TRACING_EXPORTER is file")
+	}
+	if cfg.TracingSampleRatio < 0 || cfg.TracingSampleRatio > 1 {
+		add("TRACING_SAMPLE_RATIO: must be between 0 and 1")
+	}
+
+	for _, p := range cfg.OIDCProviders {
+		prefix := "OIDC_" + strings.ToUpper(p.Name) + "_"
+		if p.Issuer == "" {
+			add("%sISSUER: is required", prefix)
+		}
+		if p.ClientID == "" {
+			add("%sCLIENT_ID: is required", prefix)
+		}
+		if p.RedirectURL == "" {
+			add("%sREDIRECT_URL: is required", prefix)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return &ValidationError{Problems: problems}
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n <= 65535
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"gogroceries/domain"
+	"gogroceries/internal/helper"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	roleUC  domain.RoleUsecase
+	adminUC domain.AdminUsecase
+}
+
+func NewAdminHandler(roleUC domain.RoleUsecase, adminUC domain.AdminUsecase) *AdminHandler {
+	return &AdminHandler{
+		roleUC:  roleUC,
+		adminUC: adminUC,
+	}
+}
+
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	page := pageRequest(c)
+
+	filter := domain.UserFilter{
+		Query:  c.Query("q"),
+		Status: c.Query("status"),
+	}
+
+	users, err := h.adminUC.ListUsers(c.Request.Context(), filter, page)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if isLegacyAPI(c) {
+		helper.SendSuccess(c, "admin.user_list", users.Legacy())
+		return
+	}
+	helper.SendPage(c, "admin.user_list", users)
+}
+
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "user.invalid_id", nil)
+		return
+	}
+
+	user, err := h.adminUC.GetUser(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "admin.user_detail", user)
+}
+
+func (h *AdminHandler) SuspendUser(c *gin.Context) {
+	adminID := c.MustGet("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "user.invalid_id", nil)
+		return
+	}
+
+	user, err := h.adminUC.SuspendUser(c.Request.Context(), uint(id), adminID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "admin.user_suspended", user)
+}
+
+func (h *AdminHandler) ReactivateUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "user.invalid_id", nil)
+		return
+	}
+
+	user, err := h.adminUC.ReactivateUser(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "admin.user_reactivated", user)
+}
+
+func (h *AdminHandler) DeleteUser(c *gin.Context) {
+	adminID := c.MustGet("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "user.invalid_id", nil)
+		return
+	}
+
+	if err := h.adminUC.DeleteUser(c.Request.Context(), uint(id), adminID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "admin.user_deleted", nil)
+}
+
+func (h *AdminHandler) GetUserRoles(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "user.invalid_id", nil)
+		return
+	}
+
+	roles, err := h.roleUC.GetUserRoles(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "admin.user_roles", roles)
+}
+
+func (h *AdminHandler) GrantRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "user.invalid_id", nil)
+		return
+	}
+
+	var req domain.GrantRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(helper.BindingError(err))
+		return
+	}
+
+	roles, err := h.roleUC.GrantRole(c.Request.Context(), uint(id), req.Role)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "admin.role_granted", roles)
+}
+
+func (h *AdminHandler) RevokeRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "user.invalid_id", nil)
+		return
+	}
+
+	roles, err := h.roleUC.RevokeRole(c.Request.Context(), uint(id), domain.Role(c.Param("role")))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "admin.role_revoked", roles)
+}
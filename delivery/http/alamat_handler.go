@@ -27,41 +27,45 @@ func (h *AlamatHandler) CreateAlamat(c *gin.Context) {
 
 	var req domain.CreateAlamatRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Invalid request payload", nil)
+		c.Error(helper.BindingError(err))
 		return
 	}
 
-	alamat, err := h.alamatUC.CreateAlamat(&req, userID)
+	alamat, err := h.alamatUC.CreateAlamat(c.Request.Context(), &req, userID)
 	if err != nil {
-		helper.SendError(c, http.StatusInternalServerError, err.Error(), nil)
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Alamat created successfully", alamat)
+	helper.SendSuccess(c, "alamat.created", alamat)
 }
 
 func (h *AlamatHandler) GetAllAlamatUser(c *gin.Context) {
 	userID := c.MustGet("user_id").(uint)
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))	
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page := pageRequest(c)
 
 	filter := domain.AlamatFilter{
 		JudulAlamat: c.Query("judul_alamat"),
 	}
 
-	alamats, pagination, err := h.alamatUC.GetAllAlamatUser(userID, filter, page, limit)
+	alamats, err := h.alamatUC.GetAllAlamatUser(c.Request.Context(), userID, filter, page)
 	if err != nil {
-		helper.SendError(c, http.StatusInternalServerError, err.Error(), nil)
+		c.Error(err)
 		return
 	}
 
-	response := map[string]interface{}{
-		"data":       alamats,
-		"pagination": pagination,
+	if isLegacyAPI(c) {
+		pagination := alamats.Legacy()
+		pagination.Data = nil
+		response := map[string]interface{}{
+			"data":       alamats.Items,
+			"pagination": pagination,
+		}
+		helper.SendSuccess(c, "alamat.list", response)
+		return
 	}
-
-	helper.SendSuccess(c, "Success get all alamat", response)
+	helper.SendPage(c, "alamat.list", alamats)
 }
 
 func (h *AlamatHandler) GetAlamatByID(c *gin.Context) {
@@ -69,22 +73,17 @@ func (h *AlamatHandler) GetAlamatByID(c *gin.Context) {
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Invalid alamat ID", nil)
+		helper.SendError(c, http.StatusBadRequest, "alamat.invalid_id", nil)
 		return
 	}
 
-	alamat, err := h.alamatUC.GetAlamatByID(uint(id), userID)
+	alamat, err := h.alamatUC.GetAlamatByID(c.Request.Context(), uint(id), userID)
 	if err != nil {
-		if err.Error() == "alamat tidak ditemukan" {
-			helper.SendError(c, http.StatusNotFound, err.Error(), nil)
-			return
-		}
-
-		helper.SendError(c, http.StatusInternalServerError, err.Error(), nil)
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Success get alamat", alamat)
+	helper.SendSuccess(c, "alamat.detail", alamat)
 }
 
 func (h *AlamatHandler) UpdateAlamat(c *gin.Context) {
@@ -92,28 +91,23 @@ func (h *AlamatHandler) UpdateAlamat(c *gin.Context) {
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Invalid alamat ID", nil)
+		helper.SendError(c, http.StatusBadRequest, "alamat.invalid_id", nil)
 		return
 	}
 
 	var req domain.UpdateAlamatRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Invalid request payload", nil)
+		c.Error(helper.BindingError(err))
 		return
 	}
 
-	alamat, err := h.alamatUC.UpdateAlamat(uint(id), &req, userID)
+	alamat, err := h.alamatUC.UpdateAlamat(c.Request.Context(), uint(id), &req, userID)
 	if err != nil {
-		if err.Error() == "alamat tidak ditemukan" {
-			helper.SendError(c, http.StatusNotFound, err.Error(), nil)
-			return
-		}
-		
-		helper.SendError(c, http.StatusInternalServerError, err.Error(), nil)
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Alamat updated successfully", alamat)
+	helper.SendSuccess(c, "alamat.updated", alamat)
 }
 
 func (h *AlamatHandler) DeleteAlamat(c *gin.Context) {
@@ -121,18 +115,14 @@ func (h *AlamatHandler) DeleteAlamat(c *gin.Context) {
 
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Invalid alamat ID", nil)
+		helper.SendError(c, http.StatusBadRequest, "alamat.invalid_id", nil)
 		return
 	}
 
-	if err := h.alamatUC.DeleteAlamat(uint(id), userID); err != nil {
-		if err.Error() == "alamat tidak ditemukan" {
-			helper.SendError(c, http.StatusNotFound, err.Error(), nil)
-			return
-		}
-		helper.SendError(c, http.StatusInternalServerError, err.Error(), nil)
+	if err := h.alamatUC.DeleteAlamat(c.Request.Context(), uint(id), userID); err != nil {
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Alamat deleted successfully", nil)
+	helper.SendSuccess(c, "alamat.deleted", nil)
 }
\ No newline at end of file
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"gogroceries/domain"
+	"gogroceries/internal/helper"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiKeyHandler struct {
+	apiKeyUC domain.ApiKeyUsecase
+}
+
+func NewApiKeyHandler(apiKeyUC domain.ApiKeyUsecase) *ApiKeyHandler {
+	return &ApiKeyHandler{
+		apiKeyUC: apiKeyUC,
+	}
+}
+
+func (h *ApiKeyHandler) GetMyApiKeys(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var tokoID uint
+	if raw := c.Query("toko_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			helper.SendError(c, http.StatusBadRequest, "toko.invalid_id", nil)
+			return
+		}
+		tokoID = uint(id)
+	}
+
+	if isLegacyAPI(c) {
+		keys, err := h.apiKeyUC.GetMyApiKeys(c.Request.Context(), tokoID, userID)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		helper.SendSuccess(c, "api_key.list", keys)
+		return
+	}
+
+	keys, err := h.apiKeyUC.ListMyApiKeys(c.Request.Context(), tokoID, userID, pageRequest(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	helper.SendPage(c, "api_key.list", keys)
+}
+
+func (h *ApiKeyHandler) CreateApiKey(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var req domain.CreateApiKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(helper.BindingError(err))
+		return
+	}
+
+	res, err := h.apiKeyUC.CreateApiKey(c.Request.Context(), &req, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "api_key.created", res)
+}
+
+func (h *ApiKeyHandler) RevokeApiKey(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "api_key.invalid_id", nil)
+		return
+	}
+
+	if err := h.apiKeyUC.RevokeApiKey(c.Request.Context(), uint(id), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "api_key.revoked", nil)
+}
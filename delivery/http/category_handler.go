@@ -23,94 +23,149 @@ func NewCategoryHandler(categoryUC domain.CategoryUsecase) *CategoryHandler {
 func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 	var req domain.CreateCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Invalid request body", nil)
+		c.Error(helper.BindingError(err))
 		return
 	}
 
-	category, err := h.categoryUsecase.CreateCategory(&req)
+	category, err := h.categoryUsecase.CreateCategory(c.Request.Context(), &req)
 	if err != nil {
-		helper.SendError(c, http.StatusInternalServerError, "Failed to create category", nil)
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Category created successfully", category)
+	helper.SendSuccess(c, "category.created", category)
 }
 
 func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
-	categories, err := h.categoryUsecase.GetAllCategories()
+	if isLegacyAPI(c) {
+		categories, err := h.categoryUsecase.GetAllCategories(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		helper.SendSuccess(c, "category.list", categories)
+		return
+	}
+
+	categories, err := h.categoryUsecase.ListCategories(c.Request.Context(), pageRequest(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	helper.SendPage(c, "category.list", categories)
+}
+
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	tree, err := h.categoryUsecase.GetCategoryTree(c.Request.Context())
 	if err != nil {
-		helper.SendError(c, http.StatusInternalServerError, "Failed to fetch categories", nil)
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Categories retrieved successfully", categories)
+	helper.SendSuccess(c, "category.list", tree)
+}
+
+func (h *CategoryHandler) GetCategoryBySlug(c *gin.Context) {
+	category, err := h.categoryUsecase.GetCategoryBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "category.detail", category)
 }
 
 func (h *CategoryHandler) GetCategoryByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Invalid category ID", nil)
+		helper.SendError(c, http.StatusBadRequest, "category.invalid_id", nil)
 		return
 	}
 
-	category, err := h.categoryUsecase.GetCategoryByID(uint(id))
-    if err != nil {
-        if err.Error() == "category tidak ditemukan" {
-            helper.SendError(c, http.StatusNotFound, err.Error(), nil)
-        } else {
-            helper.SendError(c, http.StatusInternalServerError, "Internal server error", nil)
-        }
-        return
-    }
+	category, err := h.categoryUsecase.GetCategoryByID(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
-	helper.SendSuccess(c, "Category retrieved successfully", category)
+	helper.SendSuccess(c, "category.detail", category)
 }
 
 func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Invalid category ID", nil)
+		helper.SendError(c, http.StatusBadRequest, "category.invalid_id", nil)
 		return
 	}
 
 	var req domain.UpdateCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Invalid request body", nil)
+		c.Error(helper.BindingError(err))
 		return
 	}
 
-	category, err := h.categoryUsecase.UpdateCategory(uint(id), &req)
+	category, err := h.categoryUsecase.UpdateCategory(c.Request.Context(), uint(id), &req)
 	if err != nil {
-		if err.Error() == "category tidak ditemukan" {
-			helper.SendError(c, http.StatusNotFound, err.Error(), nil)
-		} else {
-			helper.SendError(c, http.StatusInternalServerError, "Internal server error", nil)
-		}
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Category updated successfully", category)
+	helper.SendSuccess(c, "category.updated", category)
 }
 
 func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Invalid category ID", nil)
+		helper.SendError(c, http.StatusBadRequest, "category.invalid_id", nil)
 		return
 	}
 
-	err = h.categoryUsecase.DeleteCategory(uint(id))
+	err = h.categoryUsecase.DeleteCategory(c.Request.Context(), uint(id))
 	if err != nil {
-		if err.Error() == "category tidak ditemukan" {
-			helper.SendError(c, http.StatusNotFound, err.Error(), nil)
-		} else {
-			helper.SendError(c, http.StatusInternalServerError, "Internal server error", nil)
-		}
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Category deleted successfully", nil)
-}
\ No newline at end of file
+	helper.SendSuccess(c, "category.deleted", nil)
+}
+
+func (h *CategoryHandler) GetCategoryUsage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "category.invalid_id", nil)
+		return
+	}
+
+	usage, err := h.categoryUsecase.GetCategoryUsage(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "category.usage", usage)
+}
+
+func (h *CategoryHandler) MergeCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "category.invalid_id", nil)
+		return
+	}
+
+	var req domain.MergeCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(helper.BindingError(err))
+		return
+	}
+
+	result, err := h.categoryUsecase.MergeCategory(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "category.merged", result)
+}
@@ -10,17 +10,21 @@ import (
 
 type AuthHandler struct {
 	authUsecase domain.AuthUsecase
+	oidcUsecase domain.OIDCUsecase
 }
 
-func NewAuthHandler(router *gin.RouterGroup, uc domain.AuthUsecase) {
+func NewAuthHandler(router *gin.RouterGroup, uc domain.AuthUsecase, oidcUC domain.OIDCUsecase) {
 	handler := &AuthHandler{
 		authUsecase: uc,
+		oidcUsecase: oidcUC,
 	}
 
 	authRoutes := router.Group("/auth")
 	{
 		authRoutes.POST("/register", handler.Register)
 		authRoutes.POST("/login", handler.Login)
+		authRoutes.GET("/oidc/:provider/login", handler.OIDCLogin)
+		authRoutes.GET("/oidc/:provider/callback", handler.OIDCCallback)
 	}
 }
 
@@ -28,42 +32,70 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	var req domain.RegisterRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Input is not valid", err.Error())
+		c.Error(helper.BindingError(err))
 		return
 	}
 
-	newUser, err := h.authUsecase.Register(&req)
+	newUser, err := h.authUsecase.Register(c.Request.Context(), &req)
 	if err != nil {
-		if err.Error() == "email already registered" || err.Error() == "phone number already registered" {
-			helper.SendError(c, http.StatusBadRequest, "Registration failed", err.Error())
-		} else {
-			helper.SendError(c, http.StatusInternalServerError, "Failed to register", err.Error())
-		}
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Registration success", newUser)
+	helper.SendSuccess(c, "auth.register_success", newUser)
 }
 
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req domain.LoginRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Input is not valid", err.Error())
+		c.Error(helper.BindingError(err))
 		return
 	}
 
-	token, user, err := h.authUsecase.Login(&req)
+	token, user, err := h.authUsecase.Login(c.Request.Context(), &req)
 	if err != nil {
-		if err.Error() == "phone number or password is incorrect" {
-			helper.SendError(c, http.StatusUnauthorized, "Login failed", err.Error())
-		} else {
-			helper.SendError(c, http.StatusInternalServerError, "Failed to login", err.Error())
-		}
+		c.Error(err)
 		return
 	}
 
-	loginResp := domain.LoginResponse{
+	helper.SendSuccess(c, "auth.login_success", newLoginResponse(user, token))
+}
+
+func (h *AuthHandler) OIDCLogin(c *gin.Context) {
+	authURL, err := h.oidcUsecase.LoginURL(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+func (h *AuthHandler) OIDCCallback(c *gin.Context) {
+	if errCode := c.Query("error"); errCode != "" {
+		helper.SendError(c, http.StatusUnauthorized, "auth.login_failed", errCode+": "+c.Query("error_description"))
+		return
+	}
+
+	state := c.Query("state")
+	code := c.Query("code")
+	if state == "" || code == "" {
+		helper.SendError(c, http.StatusBadRequest, "error.invalid_input", "state and code are required")
+		return
+	}
+
+	token, user, err := h.oidcUsecase.Callback(c.Request.Context(), c.Param("provider"), state, code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "auth.login_success", newLoginResponse(user, token))
+}
+
+func newLoginResponse(user *domain.User, token string) domain.LoginResponse {
+	return domain.LoginResponse{
 		Nama:         user.Nama,
 		NoTelp:       user.NoTelp,
 		TanggalLahir:       user.TanggalLahir,
@@ -74,6 +106,4 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		IdProvinsi:   user.IdProvinsi,
 		IdKota:       user.IdKota,
 	}
-
-	helper.SendSuccess(c, "Login success", loginResp)
 }
\ No newline at end of file
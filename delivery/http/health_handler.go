@@ -0,0 +1,65 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"gogroceries/internal/helper"
+	"gogroceries/internal/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const readinessTimeout = 2 * time.Second
+
+// ReadinessCheck reports whether a dependency the API needs is usable.
+type ReadinessCheck func(ctx context.Context) error
+
+type HealthHandler struct {
+	checks map[string]ReadinessCheck
+}
+
+func NewHealthHandler(checks map[string]ReadinessCheck) *HealthHandler {
+	return &HealthHandler{
+		checks: checks,
+	}
+}
+
+// Liveness only tells the orchestrator the process is serving requests.
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	helper.SendSuccess(c, "health.ok", nil)
+}
+
+// Readiness runs every check and fails with 503 when one of them does. The
+// endpoint is public, so failures are only reported as "fail" and the cause
+// goes to the log.
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make(map[string]string, len(names))
+	ready := true
+	for _, name := range names {
+		if err := h.checks[name](ctx); err != nil {
+			logger.FromContext(ctx).Warn("readiness check failed", "check", name, "error", err)
+			results[name] = "fail"
+			ready = false
+			continue
+		}
+		results[name] = "ok"
+	}
+
+	if !ready {
+		helper.SendError(c, http.StatusServiceUnavailable, "health.not_ready", results)
+		return
+	}
+	helper.SendSuccess(c, "health.ready", results)
+}
@@ -0,0 +1,44 @@
+package http
+
+import (
+	"gogroceries/domain"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPageLimit caps limit on v2; v1 never had a maximum.
+const maxPageLimit = 100
+
+// pageRequest reads page/limit or cursor, and with_total. The total defaults
+// to on for page numbers, as existing clients expect, and to off for cursors.
+func pageRequest(c *gin.Context) domain.PageRequest {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit > maxPageLimit && !isLegacyAPI(c) {
+		limit = maxPageLimit
+	}
+	cursor := c.Query("cursor")
+
+	withTotal, err := strconv.ParseBool(c.Query("with_total"))
+	if err != nil {
+		withTotal = cursor == ""
+	}
+
+	return domain.PageRequest{Page: page, Limit: limit, Cursor: cursor, WithTotal: withTotal}
+}
+
+const apiVersionKey = "api_version"
+
+// apiVersion marks the requests of a versioned route group, so list handlers
+// can keep the v1 response shapes while v2 returns domain.Page.
+func apiVersion(version int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(apiVersionKey, version)
+		c.Next()
+	}
+}
+
+func isLegacyAPI(c *gin.Context) bool {
+	return c.GetInt(apiVersionKey) < 2
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"errors"
 	"fmt"
+	"gogroceries/delivery/middleware"
 	"gogroceries/domain"
 	"gogroceries/internal/helper"
 	"net/http"
@@ -14,7 +15,6 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"gorm.io/gorm" 
 )
 
 const produkUploadDir = "./uploads/produk" 
@@ -38,32 +38,45 @@ func NewProdukHandler(uc domain.ProdukUsecase, jwtAuth helper.JWTInterface) *Pro
 func (h *ProdukHandler) CreateProduk(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		helper.SendError(c, http.StatusUnauthorized, "User ID tidak ditemukan di context", nil)
+		helper.SendError(c, http.StatusUnauthorized, "auth.user_id_missing", nil)
 		return
 	}
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		helper.SendError(c, http.StatusInternalServerError, "User ID di context bukan uint", nil)
+		helper.SendError(c, http.StatusInternalServerError, "auth.user_id_invalid", nil)
 		return
 	}
 
-	if err := c.Request.ParseMultipartForm(10 << 20); err != nil { 
-		helper.SendError(c, http.StatusBadRequest, "Gagal parse form data", err.Error())
+	form, err := c.MultipartForm()
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			helper.SendError(c, http.StatusRequestEntityTooLarge, "upload.too_large", nil)
+			return
+		}
+		helper.SendError(c, http.StatusBadRequest, "upload.invalid_form", err.Error())
 		return
 	}
 
 	var req domain.CreateProdukRequest
 	if err := c.ShouldBind(&req); err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Input form tidak valid", err.Error())
+		c.Error(helper.BindingError(err))
 		return
 	}
 
-	form, _ := c.MultipartForm()
+	if keyTokoID, isApiKey := middleware.ApiKeyToko(c); isApiKey {
+		if req.IdToko != 0 && req.IdToko != keyTokoID {
+			helper.SendError(c, http.StatusForbidden, "auth.api_key_wrong_toko", nil)
+			return
+		}
+		req.IdToko = keyTokoID
+	}
+
 	files := form.File["photos"]
 	photoFilenames := []string{}
 
 	if len(files) == 0 {
-		helper.SendError(c, http.StatusBadRequest, "Minimal 1 foto produk dibutuhkan", nil)
+		helper.SendError(c, http.StatusBadRequest, "produk.photo_required", nil)
 		return
 	}
 
@@ -71,7 +84,7 @@ func (h *ProdukHandler) CreateProduk(c *gin.Context) {
 		ext := filepath.Ext(file.Filename)
 		lowerExt := strings.ToLower(ext)
 		if lowerExt != ".jpg" && lowerExt != ".png" && lowerExt != ".jpeg" {
-			helper.SendError(c, http.StatusBadRequest, "Format file tidak didukung: "+ext, nil)
+			helper.SendError(c, http.StatusBadRequest, "upload.unsupported_format", ext)
 			return
 		}
 
@@ -79,148 +92,162 @@ func (h *ProdukHandler) CreateProduk(c *gin.Context) {
 		dst := filepath.Join(produkUploadDir, newFileName)
 
 		if err := c.SaveUploadedFile(file, dst); err != nil {
-			helper.SendError(c, http.StatusInternalServerError, "Gagal menyimpan file upload", err.Error())
+			helper.SendError(c, http.StatusInternalServerError, "upload.save_failed", err.Error())
 			return
 		}
 		photoFilenames = append(photoFilenames, newFileName)
 	}
 	req.Photos = photoFilenames
 
-	newProduk, err := h.produkUsecase.CreateProduk(&req, userIDUint)
+	newProduk, err := h.produkUsecase.CreateProduk(c.Request.Context(), &req, userIDUint)
 	if err != nil {
-		helper.SendError(c, http.StatusInternalServerError, "Gagal membuat produk", err.Error())
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Produk berhasil dibuat", newProduk)
+	helper.SendSuccess(c, "produk.created", newProduk)
 	c.Status(http.StatusCreated)
 }
 
 func (h *ProdukHandler) GetAllProduk(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if page < 1 { page = 1 }
-	if limit < 1 { limit = 10 }
+	page := pageRequest(c)
 
 	categoryID, _ := strconv.ParseUint(c.Query("category_id"), 10, 32)
 	tokoID, _ := strconv.ParseUint(c.Query("toko_id"), 10, 32)
 	minHarga, _ := strconv.Atoi(c.Query("min_harga"))
 	maxHarga, _ := strconv.Atoi(c.Query("max_harga"))
+	inStock, _ := strconv.ParseBool(c.Query("in_stock"))
+
+	// nama_produk is the old name of the search parameter.
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		q = strings.TrimSpace(c.Query("nama_produk"))
+	}
 
 	filter := domain.ProdukFilter{
-		NamaProduk: c.Query("nama_produk"),
+		Query:      q,
 		CategoryID: uint(categoryID),
 		TokoID:     uint(tokoID),
 		MinHarga:   minHarga,
 		MaxHarga:   maxHarga,
+		InStock:    inStock,
+		Sort:       domain.ProdukSort(c.Query("sort")),
 	}
 
-	produks, paginationInfo, err := h.produkUsecase.GetAllProduk(filter, page, limit)
+	produks, facets, err := h.produkUsecase.GetAllProduk(c.Request.Context(), filter, page)
 	if err != nil {
-		helper.SendError(c, http.StatusInternalServerError, "Gagal mengambil daftar produk", err.Error())
+		c.Error(err)
 		return
 	}
 
-	if paginationInfo != nil {
-		paginationInfo.Data = produks
-		helper.SendSuccess(c, "Berhasil mengambil daftar produk", paginationInfo)
-	} else {
-		helper.SendSuccess(c, "Berhasil mengambil daftar produk", produks)
+	if isLegacyAPI(c) {
+		helper.SendSuccess(c, "produk.list", domain.ProdukListResponse{PaginationResponse: *produks.Legacy(), Facets: facets})
+		return
 	}
-
+	helper.SetPageLinks(c, produks)
+	helper.SendSuccess(c, "produk.list", domain.ProdukPage{Page: *produks, Facets: facets})
 }
 
 func (h *ProdukHandler) GetProdukByID(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		helper.SendError(c, http.StatusBadRequest, "ID produk tidak valid: "+idStr, nil)
+		helper.SendError(c, http.StatusBadRequest, "produk.invalid_id", idStr)
 		return
 	}
 
-	produk, err := h.produkUsecase.GetProdukByID(uint(id))
+	produk, err := h.produkUsecase.GetProdukByID(c.Request.Context(), uint(id))
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(strings.ToLower(err.Error()), "tidak ditemukan") {
-			helper.SendError(c, http.StatusNotFound, "Produk tidak ditemukan", nil)
-		} else {
-			helper.SendError(c, http.StatusInternalServerError, "Gagal mengambil detail produk", err.Error())
-		}
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Berhasil mengambil detail produk", produk)
+	helper.SendSuccess(c, "produk.detail", produk)
 }
 
 func (h *ProdukHandler) UpdateProduk(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		helper.SendError(c, http.StatusBadRequest, "ID produk tidak valid: "+idStr, nil)
+		helper.SendError(c, http.StatusBadRequest, "produk.invalid_id", idStr)
 		return
 	}
 
 	userID, exists := c.Get("user_id")
 	if !exists {
-		helper.SendError(c, http.StatusUnauthorized, "User ID tidak ditemukan di context", nil)
+		helper.SendError(c, http.StatusUnauthorized, "auth.user_id_missing", nil)
 		return
 	}
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		helper.SendError(c, http.StatusInternalServerError, "User ID di context bukan uint", nil)
+		helper.SendError(c, http.StatusInternalServerError, "auth.user_id_invalid", nil)
 		return
 	}
 
 	var req domain.UpdateProdukRequest
 	if err := c.ShouldBind(&req); err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Input form tidak valid", err.Error())
+		c.Error(helper.BindingError(err))
+		return
+	}
+
+	if !h.apiKeyAllowsProduk(c, uint(id)) {
 		return
 	}
 
-	updatedProduk, err := h.produkUsecase.UpdateProduk(uint(id), &req, userIDUint)
+	updatedProduk, err := h.produkUsecase.UpdateProduk(c.Request.Context(), uint(id), &req, userIDUint)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(strings.ToLower(err.Error()), "tidak ditemukan") {
-			helper.SendError(c, http.StatusNotFound, "Produk tidak ditemukan", nil)
-		} else if strings.Contains(strings.ToLower(err.Error()), "forbidden") || strings.Contains(strings.ToLower(err.Error()), "tidak bisa mengubah") {
-			helper.SendError(c, http.StatusForbidden, "Anda tidak punya akses untuk mengubah produk ini", nil)
-		} else {
-			helper.SendError(c, http.StatusInternalServerError, "Gagal update produk", err.Error())
-		}
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Produk berhasil diupdate", updatedProduk)
+	helper.SendSuccess(c, "produk.updated", updatedProduk)
 }
 
 func (h *ProdukHandler) DeleteProduk(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		helper.SendError(c, http.StatusBadRequest, "ID produk tidak valid: "+idStr, nil)
+		helper.SendError(c, http.StatusBadRequest, "produk.invalid_id", idStr)
 		return
 	}
 
 	userID, exists := c.Get("user_id")
 	if !exists {
-		helper.SendError(c, http.StatusUnauthorized, "User ID tidak ditemukan di context", nil)
+		helper.SendError(c, http.StatusUnauthorized, "auth.user_id_missing", nil)
 		return
 	}
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		helper.SendError(c, http.StatusInternalServerError, "User ID di context bukan uint", nil)
+		helper.SendError(c, http.StatusInternalServerError, "auth.user_id_invalid", nil)
 		return
 	}
 
-	err = h.produkUsecase.DeleteProduk(uint(id), userIDUint)
+	if !h.apiKeyAllowsProduk(c, uint(id)) {
+		return
+	}
+
+	err = h.produkUsecase.DeleteProduk(c.Request.Context(), uint(id), userIDUint)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(strings.ToLower(err.Error()), "tidak ditemukan") {
-			helper.SendError(c, http.StatusNotFound, "Produk tidak ditemukan", nil)
-		} else if strings.Contains(strings.ToLower(err.Error()), "forbidden") || strings.Contains(strings.ToLower(err.Error()), "tidak bisa menghapus") {
-			helper.SendError(c, http.StatusForbidden, "Anda tidak punya akses untuk menghapus produk ini", nil)
-		} else {
-			helper.SendError(c, http.StatusInternalServerError, "Gagal menghapus produk", err.Error())
-		}
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Produk berhasil dihapus", fmt.Sprintf("Produk dengan ID %d telah dihapus", id))
-}
\ No newline at end of file
+	helper.SendSuccess(c, "produk.deleted", fmt.Sprintf("Produk dengan ID %d telah dihapus", id))
+}
+
+func (h *ProdukHandler) apiKeyAllowsProduk(c *gin.Context, id uint) bool {
+	if _, isApiKey := middleware.ApiKeyToko(c); !isApiKey {
+		return true
+	}
+
+	produk, err := h.produkUsecase.GetProdukByID(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return false
+	}
+	if !middleware.ApiKeyAllowsToko(c, produk.IdToko) {
+		helper.SendError(c, http.StatusForbidden, "auth.api_key_wrong_toko", nil)
+		return false
+	}
+	return true
+}
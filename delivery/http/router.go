@@ -5,88 +5,165 @@ import (
 	"gogroceries/delivery/middleware"
 	"gogroceries/domain"
 	"gogroceries/internal/helper"
+	"gogroceries/internal/metrics"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func SetupRouter(
 	engine *gin.Engine,
 	cfg config.Config,
 	authUC domain.AuthUsecase,
+	oidcUC domain.OIDCUsecase,
 	userUC domain.UserUsecase,
 	tokoUC domain.TokoUsecase, 
 	produkUC domain.ProdukUsecase, 
 	categoryUC domain.CategoryUsecase, 
 	trxUC domain.TrxUsecase, 
 	alamatUC domain.AlamatUsecase,
+	roleUC domain.RoleUsecase,
+	adminUC domain.AdminUsecase,
+	tokoMemberUC domain.TokoMemberUsecase,
+	apiKeyUC domain.ApiKeyUsecase,
 	jwtAuth helper.JWTInterface,
+	readinessChecks map[string]ReadinessCheck,
 ) {
+	engine.MaxMultipartMemory = cfg.UploadMaxSize
+	engine.Use(middleware.Tracing(), middleware.RequestLogger(), middleware.Metrics(), middleware.Recovery(), middleware.ErrorHandler(), middleware.CORS(cfg), middleware.BodyLimit(cfg.UploadMaxSize), middleware.DBTimeout(cfg.DBQueryTimeout))
+
 	engine.GET("/", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "Hello World"})
 	})
 
-	apiV1 := engine.Group("/api/v1")
+	healthHandler := NewHealthHandler(readinessChecks)
+	engine.GET("/healthz", healthHandler.Liveness)
+	engine.GET("/readyz", healthHandler.Readiness)
+	engine.GET("/metrics", middleware.MetricsAuth(cfg.MetricsToken), gin.WrapH(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})))
+
+	engine.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, jwtAuth.JWKS())
+	})
 
-	NewAuthHandler(apiV1, authUC) 
+	// /api/v2 serves the same routes; only the list endpoints differ, returning
+	// domain.Page with Link headers instead of the v1 shapes.
+	registerAPI := func(api *gin.RouterGroup) {
+		NewAuthHandler(api, authUC, oidcUC) 
 
-	userRoutes := apiV1.Group("/user")
-	userRoutes.Use(middleware.AuthMiddleware(jwtAuth)) 
-	{
-		userHandler := NewUserHandler(userUC, jwtAuth)
-		alamatHandler := NewAlamatHandler(alamatUC, jwtAuth)
+		userRoutes := api.Group("/user")
+		userRoutes.Use(middleware.AuthMiddleware(jwtAuth, userUC)) 
+		{
+			userHandler := NewUserHandler(userUC, jwtAuth)
+			alamatHandler := NewAlamatHandler(alamatUC, jwtAuth)
 
-		userRoutes.GET("", userHandler.GetMyProfile) 
-		userRoutes.PUT("", userHandler.UpdateProfile) 
+			userRoutes.GET("", userHandler.GetMyProfile) 
+			userRoutes.PUT("", userHandler.UpdateProfile) 
+			userRoutes.DELETE("", userHandler.DeleteAccount)
+			userRoutes.GET("/export", userHandler.ExportData)
 
-		alamatRoutes := userRoutes.Group("/alamat")
+			alamatRoutes := userRoutes.Group("/alamat")
+			{
+				alamatRoutes.GET("", alamatHandler.GetAllAlamatUser)    
+				alamatRoutes.POST("", alamatHandler.CreateAlamat)   
+				alamatRoutes.GET("/:id", alamatHandler.GetAlamatByID) 
+				alamatRoutes.PUT("/:id", alamatHandler.UpdateAlamat) 
+				alamatRoutes.DELETE("/:id", alamatHandler.DeleteAlamat) 
+			}
+		}
+		tokoRoutes := api.Group("/toko")
+		tokoHandler := NewTokoHandler(tokoUC, jwtAuth)
+		tokoMemberHandler := NewTokoMemberHandler(tokoMemberUC)
+		tokoTrxHandler := NewTrxHandler(trxUC, jwtAuth)
+		apiKeyHandler := NewApiKeyHandler(apiKeyUC)
 		{
-			alamatRoutes.GET("", alamatHandler.GetAllAlamatUser)    
-			alamatRoutes.POST("", alamatHandler.CreateAlamat)   
-			alamatRoutes.GET("/:id", alamatHandler.GetAlamatByID) 
-			alamatRoutes.PUT("/:id", alamatHandler.UpdateAlamat) 
-			alamatRoutes.DELETE("/:id", alamatHandler.DeleteAlamat) 
+			tokoRoutes.GET("/my", middleware.AuthMiddleware(jwtAuth, userUC), tokoHandler.GetMyToko)
+			tokoRoutes.GET("/my/api-keys", middleware.AuthMiddleware(jwtAuth, userUC), apiKeyHandler.GetMyApiKeys)
+			tokoRoutes.POST("/my/api-keys", middleware.AuthMiddleware(jwtAuth, userUC), apiKeyHandler.CreateApiKey)
+			tokoRoutes.DELETE("/my/api-keys/:id", middleware.AuthMiddleware(jwtAuth, userUC), apiKeyHandler.RevokeApiKey)
+			tokoRoutes.PUT("/:id_toko", middleware.AuthMiddleware(jwtAuth, userUC), tokoHandler.UpdateToko) 
+			tokoRoutes.GET("", tokoHandler.GetAllToko) 
+			tokoRoutes.GET("/:id_toko", tokoHandler.GetTokoByID) 
+
+			tokoRoutes.GET("/memberships", middleware.AuthMiddleware(jwtAuth, userUC), tokoMemberHandler.GetMyMemberships)
+			tokoRoutes.GET("/invitations", middleware.AuthMiddleware(jwtAuth, userUC), tokoMemberHandler.GetMyInvitations)
+			tokoRoutes.POST("/invitations/:id/accept", middleware.AuthMiddleware(jwtAuth, userUC), tokoMemberHandler.AcceptInvitation)
+			tokoRoutes.POST("/invitations/:id/decline", middleware.AuthMiddleware(jwtAuth, userUC), tokoMemberHandler.DeclineInvitation)
+
+			tokoRoutes.GET("/:id_toko/members", middleware.AuthMiddleware(jwtAuth, userUC), tokoMemberHandler.GetMembers)
+			tokoRoutes.PUT("/:id_toko/members/:user_id", middleware.AuthMiddleware(jwtAuth, userUC), tokoMemberHandler.UpdateMemberRole)
+			tokoRoutes.DELETE("/:id_toko/members/:user_id", middleware.AuthMiddleware(jwtAuth, userUC), tokoMemberHandler.RemoveMember)
+			tokoRoutes.GET("/:id_toko/invitations", middleware.AuthMiddleware(jwtAuth, userUC), tokoMemberHandler.GetTokoInvitations)
+			tokoRoutes.POST("/:id_toko/invitations", middleware.AuthMiddleware(jwtAuth, userUC), tokoMemberHandler.InviteMember)
+			tokoRoutes.DELETE("/:id_toko/invitations/:id", middleware.AuthMiddleware(jwtAuth, userUC), tokoMemberHandler.CancelInvitation)
+
+			tokoRoutes.GET("/:id_toko/orders", middleware.AuthOrAPIKeyMiddleware(jwtAuth, userUC, apiKeyUC, domain.TokoPermissionOrdersRead), tokoTrxHandler.GetAllTransaksiToko)
+			tokoRoutes.GET("/:id_toko/orders/:id", middleware.AuthOrAPIKeyMiddleware(jwtAuth, userUC, apiKeyUC, domain.TokoPermissionOrdersRead), tokoTrxHandler.GetTransaksiTokoByID)
+			tokoRoutes.PUT("/:id_toko/orders/:id/status", middleware.AuthOrAPIKeyMiddleware(jwtAuth, userUC, apiKeyUC, domain.TokoPermissionOrdersWrite), tokoTrxHandler.UpdateStatusTransaksiToko)
 		}
-	}
-	tokoRoutes := apiV1.Group("/toko")
-	tokoHandler := NewTokoHandler(tokoUC, jwtAuth)
-	{
-		tokoRoutes.GET("/my", middleware.AuthMiddleware(jwtAuth), tokoHandler.GetMyToko)
-		tokoRoutes.PUT("/:id_toko", middleware.AuthMiddleware(jwtAuth), tokoHandler.UpdateToko) 
-		tokoRoutes.GET("", tokoHandler.GetAllToko) 
-		tokoRoutes.GET("/:id_toko", tokoHandler.GetTokoByID) 
-	}
 
-	productRoutes := apiV1.Group("/product")
-	produkHandler := NewProdukHandler(produkUC, jwtAuth)
-	{
-		productRoutes.POST("", middleware.AuthMiddleware(jwtAuth), produkHandler.CreateProduk)   
-		productRoutes.PUT("/:id", middleware.AuthMiddleware(jwtAuth), produkHandler.UpdateProduk) 
-		productRoutes.DELETE("/:id", middleware.AuthMiddleware(jwtAuth), produkHandler.DeleteProduk) 
-		productRoutes.GET("", produkHandler.GetAllProduk)    
-		productRoutes.GET("/:id", produkHandler.GetProdukByID) 
-	}
+		productRoutes := api.Group("/product")
+		produkHandler := NewProdukHandler(produkUC, jwtAuth)
+		{
+			productRoutes.POST("", middleware.AuthOrAPIKeyMiddleware(jwtAuth, userUC, apiKeyUC, domain.TokoPermissionProdukWrite), produkHandler.CreateProduk)   
+			productRoutes.PUT("/:id", middleware.AuthOrAPIKeyMiddleware(jwtAuth, userUC, apiKeyUC, domain.TokoPermissionProdukWrite), produkHandler.UpdateProduk) 
+			productRoutes.DELETE("/:id", middleware.AuthOrAPIKeyMiddleware(jwtAuth, userUC, apiKeyUC, domain.TokoPermissionProdukWrite), produkHandler.DeleteProduk) 
+			productRoutes.GET("", produkHandler.GetAllProduk)    
+			productRoutes.GET("/:id", produkHandler.GetProdukByID) 
+		}
+
+		categoryRoutes := api.Group("/category")
+		categoryHandler := NewCategoryHandler(categoryUC)
+		{
+			categoryRoutes.GET("", categoryHandler.GetAllCategories)    
+			categoryRoutes.GET("/:id", categoryHandler.GetCategoryByID) 
+			categoryRoutes.GET("/tree", categoryHandler.GetCategoryTree)
+			categoryRoutes.GET("/slug/:slug", categoryHandler.GetCategoryBySlug)
 
-	categoryRoutes := apiV1.Group("/category")
-	categoryHandler := NewCategoryHandler(categoryUC)
-	{
-		categoryRoutes.GET("", categoryHandler.GetAllCategories)    
-		categoryRoutes.GET("/:id", categoryHandler.GetCategoryByID) 
+			adminCategoryRoutes := categoryRoutes.Use(middleware.AuthMiddleware(jwtAuth, userUC), middleware.RequirePermission(domain.PermissionCategoryManage))
+			{
+				adminCategoryRoutes.POST("", categoryHandler.CreateCategory)  
+				adminCategoryRoutes.PUT("/:id", categoryHandler.UpdateCategory) 
+				adminCategoryRoutes.DELETE("/:id", categoryHandler.DeleteCategory) 
+				adminCategoryRoutes.GET("/:id/usage", categoryHandler.GetCategoryUsage)
+				adminCategoryRoutes.POST("/:id/merge", categoryHandler.MergeCategory)
+			}
+		}
 
-		adminCategoryRoutes := categoryRoutes.Use(middleware.AuthMiddleware(jwtAuth), middleware.AdminMiddleware())
+		trxRoutes := api.Group("/trx")
+		trxRoutes.Use(middleware.AuthMiddleware(jwtAuth, userUC)) 
 		{
-			adminCategoryRoutes.POST("", categoryHandler.CreateCategory)  
-			adminCategoryRoutes.PUT("/:id", categoryHandler.UpdateCategory) 
-			adminCategoryRoutes.DELETE("/:id", categoryHandler.DeleteCategory) 
+			trxHandler := NewTrxHandler(trxUC, jwtAuth) 
+			trxRoutes.POST("", trxHandler.CreateTransaksi)  
+			trxRoutes.GET("", trxHandler.GetAllTransaksiUser)    
+			trxRoutes.GET("/:id", trxHandler.GetTransaksiByID) 
 		}
-	}
 
-	trxRoutes := apiV1.Group("/trx")
-	trxRoutes.Use(middleware.AuthMiddleware(jwtAuth)) 
-	{
-		trxHandler := NewTrxHandler(trxUC, jwtAuth) 
-		trxRoutes.POST("", trxHandler.CreateTransaksi)  
-		trxRoutes.GET("", trxHandler.GetAllTransaksiUser)    
-		trxRoutes.GET("/:id", trxHandler.GetTransaksiByID) 
+		// Orders across all tokos, for platform staff such as customer service
+		// and couriers.
+		orderRoutes := api.Group("/orders")
+		orderRoutes.Use(middleware.AuthMiddleware(jwtAuth, userUC))
+		{
+			orderHandler := NewTrxHandler(trxUC, jwtAuth)
+			orderRoutes.GET("", middleware.RequirePermission(domain.PermissionOrderRead), orderHandler.GetAllTransaksi)
+			orderRoutes.PUT("/:id/status", middleware.RequireAnyPermission(domain.PermissionOrderManage, domain.PermissionOrderDeliver), orderHandler.UpdateStatusTransaksi)
+		}
+
+		adminRoutes := api.Group("/admin")
+		adminRoutes.Use(middleware.AuthMiddleware(jwtAuth, userUC))
+		{
+			adminHandler := NewAdminHandler(roleUC, adminUC)
+			adminRoutes.GET("/users", middleware.RequirePermission(domain.PermissionUserRead), adminHandler.ListUsers)
+			adminRoutes.GET("/users/:id", middleware.RequirePermission(domain.PermissionUserRead), adminHandler.GetUser)
+			adminRoutes.POST("/users/:id/suspend", middleware.RequirePermission(domain.PermissionUserManage), adminHandler.SuspendUser)
+			adminRoutes.POST("/users/:id/reactivate", middleware.RequirePermission(domain.PermissionUserManage), adminHandler.ReactivateUser)
+			adminRoutes.DELETE("/users/:id", middleware.RequirePermission(domain.PermissionUserManage), adminHandler.DeleteUser)
+			adminRoutes.GET("/users/:id/roles", middleware.RequirePermission(domain.PermissionUserRead), adminHandler.GetUserRoles)
+			adminRoutes.POST("/users/:id/roles", middleware.RequirePermission(domain.PermissionRoleManage), adminHandler.GrantRole)
+			adminRoutes.DELETE("/users/:id/roles/:role", middleware.RequirePermission(domain.PermissionRoleManage), adminHandler.RevokeRole)
+		}
 	}
+	registerAPI(engine.Group("/api/v1", apiVersion(1)))
+	registerAPI(engine.Group("/api/v2", apiVersion(2)))
 }
\ No newline at end of file
@@ -25,13 +25,13 @@ func NewTokoHandler(tokoUC domain.TokoUsecase, jwtAuth helper.JWTInterface) *Tok
 func (h *TokoHandler) GetMyToko(c *gin.Context) {
 	userID := c.MustGet("user_id").(uint)
 
-	toko, err := h.tokoUC.GetMyToko(userID)
+	toko, err := h.tokoUC.GetMyToko(c.Request.Context(), userID)
 	if err != nil {
-		helper.SendError(c, http.StatusNotFound, err.Error(), nil)
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Success get my toko", toko)
+	helper.SendSuccess(c, "toko.mine", toko)
 }
 
 func (h *TokoHandler) UpdateToko(c *gin.Context) {
@@ -39,13 +39,13 @@ func (h *TokoHandler) UpdateToko(c *gin.Context) {
 
 	id, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
 	if err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Invalid toko ID", nil)
+		helper.SendError(c, http.StatusBadRequest, "toko.invalid_id", nil)
 		return
 	}
 
 	var req domain.UpdateTokoRequest
 	if err := c.ShouldBind(&req); err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Invalid request payload", nil)
+		c.Error(helper.BindingError(err))
 		return
 	}
 
@@ -54,49 +54,53 @@ func (h *TokoHandler) UpdateToko(c *gin.Context) {
 		req.UrlFoto = "path/to/uploaded/" + file.Filename
 	}
 
-	toko, err := h.tokoUC.UpdateToko(uint(id), &req, userID)
+	toko, err := h.tokoUC.UpdateToko(c.Request.Context(), uint(id), &req, userID)
 	if err != nil {
-		helper.SendError(c, http.StatusNotFound, err.Error(), nil)
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Toko updated successfully", toko)
+	helper.SendSuccess(c, "toko.updated", toko)
 }
 
 func (h *TokoHandler) GetAllToko(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	page := pageRequest(c)
 
 	filter := domain.TokoFilter{
 		NamaToko: c.Query("nama_toko"),
 	}
 
-	tokos, pagination, err := h.tokoUC.GetAllTokos(filter, page, limit)
+	tokos, err := h.tokoUC.GetAllTokos(c.Request.Context(), filter, page)
 	if err != nil {
-		helper.SendError(c, http.StatusNotFound, err.Error(), nil)
+		c.Error(err)
 		return
 	}
 
-	response := map[string]interface{}{
-		"data":       tokos,
-		"pagination": pagination,
+	if isLegacyAPI(c) {
+		pagination := tokos.Legacy()
+		pagination.Data = nil
+		response := map[string]interface{}{
+			"data":       tokos.Items,
+			"pagination": pagination,
+		}
+		helper.SendSuccess(c, "toko.list", response)
+		return
 	}
-
-	helper.SendSuccess(c, "Success get all tokos", response)
+	helper.SendPage(c, "toko.list", tokos)
 }
 
 func (h *TokoHandler) GetTokoByID(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Invalid store ID", nil)
+		helper.SendError(c, http.StatusBadRequest, "toko.invalid_id", nil)
 		return
 	}
 
-	store, err := h.tokoUC.GetTokoByID(uint(id))
+	store, err := h.tokoUC.GetTokoByID(c.Request.Context(), uint(id))
 	if err != nil {
-		helper.SendError(c, http.StatusNotFound, err.Error(), nil)
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Success get store", store)
+	helper.SendSuccess(c, "toko.detail", store)
 }
\ No newline at end of file
@@ -0,0 +1,252 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"gogroceries/domain"
+	"gogroceries/internal/helper"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TokoMemberHandler struct {
+	memberUC domain.TokoMemberUsecase
+}
+
+func NewTokoMemberHandler(memberUC domain.TokoMemberUsecase) *TokoMemberHandler {
+	return &TokoMemberHandler{
+		memberUC: memberUC,
+	}
+}
+
+func (h *TokoMemberHandler) GetMyMemberships(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	if isLegacyAPI(c) {
+		members, err := h.memberUC.GetMyMemberships(c.Request.Context(), userID)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		helper.SendSuccess(c, "toko_member.my_memberships", members)
+		return
+	}
+
+	members, err := h.memberUC.ListMyMemberships(c.Request.Context(), userID, pageRequest(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	helper.SendPage(c, "toko_member.my_memberships", members)
+}
+
+func (h *TokoMemberHandler) GetMembers(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	tokoID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "toko.invalid_id", nil)
+		return
+	}
+
+	if isLegacyAPI(c) {
+		members, err := h.memberUC.GetMembers(c.Request.Context(), uint(tokoID), userID)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		helper.SendSuccess(c, "toko_member.list", members)
+		return
+	}
+
+	members, err := h.memberUC.ListMembers(c.Request.Context(), uint(tokoID), userID, pageRequest(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	helper.SendPage(c, "toko_member.list", members)
+}
+
+func (h *TokoMemberHandler) InviteMember(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	tokoID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "toko.invalid_id", nil)
+		return
+	}
+
+	var req domain.InviteTokoMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(helper.BindingError(err))
+		return
+	}
+
+	invitation, err := h.memberUC.InviteMember(c.Request.Context(), uint(tokoID), &req, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "toko_member.invitation_sent", invitation)
+}
+
+func (h *TokoMemberHandler) GetTokoInvitations(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	tokoID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "toko.invalid_id", nil)
+		return
+	}
+
+	if isLegacyAPI(c) {
+		invitations, err := h.memberUC.GetTokoInvitations(c.Request.Context(), uint(tokoID), userID)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		helper.SendSuccess(c, "toko_member.toko_invitations", invitations)
+		return
+	}
+
+	invitations, err := h.memberUC.ListTokoInvitations(c.Request.Context(), uint(tokoID), userID, pageRequest(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	helper.SendPage(c, "toko_member.toko_invitations", invitations)
+}
+
+func (h *TokoMemberHandler) CancelInvitation(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	tokoID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "toko.invalid_id", nil)
+		return
+	}
+
+	invitationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "toko_member.invalid_invitation_id", nil)
+		return
+	}
+
+	if err := h.memberUC.CancelInvitation(c.Request.Context(), uint(tokoID), uint(invitationID), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "toko_member.invitation_cancelled", nil)
+}
+
+func (h *TokoMemberHandler) GetMyInvitations(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	if isLegacyAPI(c) {
+		invitations, err := h.memberUC.GetMyInvitations(c.Request.Context(), userID)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		helper.SendSuccess(c, "toko_member.my_invitations", invitations)
+		return
+	}
+
+	invitations, err := h.memberUC.ListMyInvitations(c.Request.Context(), userID, pageRequest(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	helper.SendPage(c, "toko_member.my_invitations", invitations)
+}
+
+func (h *TokoMemberHandler) AcceptInvitation(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	invitationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "toko_member.invalid_invitation_id", nil)
+		return
+	}
+
+	member, err := h.memberUC.AcceptInvitation(c.Request.Context(), uint(invitationID), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "toko_member.invitation_accepted", member)
+}
+
+func (h *TokoMemberHandler) DeclineInvitation(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	invitationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "toko_member.invalid_invitation_id", nil)
+		return
+	}
+
+	if err := h.memberUC.DeclineInvitation(c.Request.Context(), uint(invitationID), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "toko_member.invitation_declined", nil)
+}
+
+func (h *TokoMemberHandler) UpdateMemberRole(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	tokoID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "toko.invalid_id", nil)
+		return
+	}
+
+	memberUserID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "user.invalid_id", nil)
+		return
+	}
+
+	var req domain.UpdateTokoMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(helper.BindingError(err))
+		return
+	}
+
+	member, err := h.memberUC.UpdateMemberRole(c.Request.Context(), uint(tokoID), uint(memberUserID), &req, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "toko_member.role_updated", member)
+}
+
+func (h *TokoMemberHandler) RemoveMember(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	tokoID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "toko.invalid_id", nil)
+		return
+	}
+
+	memberUserID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "user.invalid_id", nil)
+		return
+	}
+
+	if err := h.memberUC.RemoveMember(c.Request.Context(), uint(tokoID), uint(memberUserID), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "toko_member.removed", nil)
+}
@@ -1,15 +1,12 @@
 package http
 
 import (
-	"errors"
 	"gogroceries/domain"
 	"gogroceries/internal/helper"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 type TrxHandler struct {
@@ -27,105 +24,217 @@ func NewTrxHandler(trxUC domain.TrxUsecase, jwtAuth helper.JWTInterface) *TrxHan
 func (h *TrxHandler) CreateTransaksi(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		helper.SendError(c, http.StatusUnauthorized, "User ID tidak ditemukan di context", nil)
+		helper.SendError(c, http.StatusUnauthorized, "auth.user_id_missing", nil)
 		return
 	}
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		helper.SendError(c, http.StatusInternalServerError, "User ID di context bukan uint", nil)
+		helper.SendError(c, http.StatusInternalServerError, "auth.user_id_invalid", nil)
 		return
 	}
 
 	var req domain.CreateTransaksiRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Input JSON tidak valid", err.Error())
+		c.Error(helper.BindingError(err))
 		return
 	}
 
-	newTrx, err := h.trxUC.CreateTransaksi(&req, userIDUint)
+	newTrx, err := h.trxUC.CreateTransaksi(c.Request.Context(), &req, userIDUint)
 	if err != nil {
-		if strings.Contains(strings.ToLower(err.Error()), "tidak ditemukan") {
-			helper.SendError(c, http.StatusNotFound, "Gagal membuat transaksi", err.Error())
-		} else if strings.Contains(strings.ToLower(err.Error()), "stok") {
-			helper.SendError(c, http.StatusBadRequest, "Gagal membuat transaksi", err.Error())
-		} else if strings.Contains(strings.ToLower(err.Error()), "alamat") {
-			helper.SendError(c, http.StatusBadRequest, "Gagal membuat transaksi", err.Error())
-		} else {
-			helper.SendError(c, http.StatusInternalServerError, "Gagal membuat transaksi", err.Error())
-		}
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Transaksi berhasil dibuat", newTrx)
+	helper.SendSuccess(c, "trx.created", newTrx)
 	c.Status(http.StatusCreated)
 }
 
 func (h *TrxHandler) GetAllTransaksiUser(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		helper.SendError(c, http.StatusUnauthorized, "User ID tidak ditemukan di context", nil)
+		helper.SendError(c, http.StatusUnauthorized, "auth.user_id_missing", nil)
 		return
 	}
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		helper.SendError(c, http.StatusInternalServerError, "User ID di context bukan uint", nil)
+		helper.SendError(c, http.StatusInternalServerError, "auth.user_id_invalid", nil)
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if page < 1 { page = 1 }
-	if limit < 1 { limit = 10 }
+	page := pageRequest(c)
 
 	filter := domain.TrxFilter{
 		KodeInvoice: c.Query("kode_invoice"),
 		Status:      c.Query("status"),
 	}
 
-	trxs, paginationInfo, err := h.trxUC.GetAllTransaksiUser(userIDUint, filter, page, limit)
+	trxs, err := h.trxUC.GetAllTransaksiUser(c.Request.Context(), userIDUint, filter, page)
 	if err != nil {
-		helper.SendError(c, http.StatusInternalServerError, "Gagal mengambil daftar transaksi", err.Error())
+		c.Error(err)
 		return
 	}
 
-	if paginationInfo != nil {
-		paginationInfo.Data = trxs
-		helper.SendSuccess(c, "Berhasil mengambil daftar transaksi", paginationInfo)
-	} else {
-		helper.SendSuccess(c, "Berhasil mengambil daftar transaksi", trxs)
+	if isLegacyAPI(c) {
+		helper.SendSuccess(c, "trx.list", trxs.Legacy())
+		return
 	}
+	helper.SendPage(c, "trx.list", trxs)
 }
 
 func (h *TrxHandler) GetTransaksiByID(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		helper.SendError(c, http.StatusUnauthorized, "User ID tidak ditemukan di context", nil)
+		helper.SendError(c, http.StatusUnauthorized, "auth.user_id_missing", nil)
 		return
 	}
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		helper.SendError(c, http.StatusInternalServerError, "User ID di context bukan uint", nil)
+		helper.SendError(c, http.StatusInternalServerError, "auth.user_id_invalid", nil)
 		return
 	}
 
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		helper.SendError(c, http.StatusBadRequest, "ID transaksi tidak valid: "+idStr, nil)
+		helper.SendError(c, http.StatusBadRequest, "trx.invalid_id", idStr)
+		return
+	}
+
+	trx, err := h.trxUC.GetTransaksiByID(c.Request.Context(), uint(id), userIDUint)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "trx.detail", trx)
+}
+
+func (h *TrxHandler) GetAllTransaksiToko(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	tokoID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "toko.invalid_id", nil)
 		return
 	}
 
-	trx, err := h.trxUC.GetTransaksiByID(uint(id), userIDUint)
+	page := pageRequest(c)
+
+	filter := domain.TrxFilter{
+		KodeInvoice: c.Query("kode_invoice"),
+		Status:      c.Query("status"),
+	}
+
+	trxs, err := h.trxUC.GetAllTransaksiToko(c.Request.Context(), uint(tokoID), userID, filter, page)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) || strings.Contains(strings.ToLower(err.Error()), "tidak ditemukan") {
-			helper.SendError(c, http.StatusNotFound, "Transaksi tidak ditemukan", nil)
-		} else if strings.Contains(strings.ToLower(err.Error()), "bukan milik anda") {
-			helper.SendError(c, http.StatusForbidden, "Anda tidak punya akses ke transaksi ini", nil)
-		} else {
-			helper.SendError(c, http.StatusInternalServerError, "Gagal mengambil detail transaksi", err.Error())
-		}
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Berhasil mengambil detail transaksi", trx)
-}
\ No newline at end of file
+	if isLegacyAPI(c) {
+		helper.SendSuccess(c, "trx.toko_list", trxs.Legacy())
+		return
+	}
+	helper.SendPage(c, "trx.toko_list", trxs)
+}
+
+func (h *TrxHandler) GetTransaksiTokoByID(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	tokoID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "toko.invalid_id", nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "trx.invalid_id", nil)
+		return
+	}
+
+	trx, err := h.trxUC.GetTransaksiTokoByID(c.Request.Context(), uint(id), uint(tokoID), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "trx.toko_detail", trx)
+}
+
+func (h *TrxHandler) UpdateStatusTransaksiToko(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	tokoID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "toko.invalid_id", nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "trx.invalid_id", nil)
+		return
+	}
+
+	var req domain.UpdateTrxStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(helper.BindingError(err))
+		return
+	}
+
+	trx, err := h.trxUC.UpdateStatusTransaksiToko(c.Request.Context(), uint(id), uint(tokoID), &req, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "trx.status_updated", trx)
+}
+
+func (h *TrxHandler) GetAllTransaksi(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	page := pageRequest(c)
+
+	filter := domain.TrxFilter{
+		KodeInvoice: c.Query("kode_invoice"),
+		Status:      c.Query("status"),
+	}
+
+	trxs, err := h.trxUC.GetAllTransaksi(c.Request.Context(), userID, filter, page)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if isLegacyAPI(c) {
+		helper.SendSuccess(c, "trx.all_list", trxs.Legacy())
+		return
+	}
+	helper.SendPage(c, "trx.all_list", trxs)
+}
+
+func (h *TrxHandler) UpdateStatusTransaksi(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		helper.SendError(c, http.StatusBadRequest, "trx.invalid_id", nil)
+		return
+	}
+
+	var req domain.UpdateTrxStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(helper.BindingError(err))
+		return
+	}
+
+	trx, err := h.trxUC.UpdateStatusTransaksi(c.Request.Context(), uint(id), &req, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "trx.status_updated", trx)
+}
@@ -1,10 +1,13 @@
 package http
 
 import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"gogroceries/domain"
 	"gogroceries/internal/helper"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,53 +27,125 @@ func NewUserHandler(uc domain.UserUsecase, jwtHelper helper.JWTInterface) *UserH
 func (h *UserHandler) GetMyProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		helper.SendError(c, http.StatusUnauthorized, "Unauthorized: User ID not found in token", nil)
+		helper.SendError(c, http.StatusUnauthorized, "auth.user_id_missing", nil)
 		return
 	}
 
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		helper.SendError(c, http.StatusInternalServerError, "Invalid user ID format", nil)
+		helper.SendError(c, http.StatusInternalServerError, "user.invalid_id", nil)
 		return
 	}
 
-	user, err := h.userUsecase.GetProfileById(userIDUint)
+	user, err := h.userUsecase.GetProfileById(c.Request.Context(), userIDUint)
 	if err != nil {
-		helper.SendError(c, http.StatusInternalServerError, "Internal server error", err.Error())
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Success get user profile", user)
+	helper.SendSuccess(c, "user.profile", user)
 }
 
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		helper.SendError(c, http.StatusUnauthorized, "Unauthorized: User ID not found in token", nil)
+		helper.SendError(c, http.StatusUnauthorized, "auth.user_id_missing", nil)
 		return
 	}
 
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		helper.SendError(c, http.StatusInternalServerError, "Invalid user ID format", nil)
+		helper.SendError(c, http.StatusInternalServerError, "user.invalid_id", nil)
 		return
 	}
 
 	var req domain.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		helper.SendError(c, http.StatusBadRequest, "Invalid request body", err.Error())
+		c.Error(helper.BindingError(err))
 		return
 	}
 
-	updatedUser, err := h.userUsecase.UpdateProfile(userIDUint, &req)
+	updatedUser, err := h.userUsecase.UpdateProfile(c.Request.Context(), userIDUint, &req)
 	if err != nil {
-		if strings.Contains(err.Error(), "sudah terdaftar") {
-			helper.SendError(c, http.StatusBadRequest, "Update failed", err.Error())
-		} else {
-			helper.SendError(c, http.StatusInternalServerError, "Update failed", err.Error())
+		c.Error(err)
+		return
+	}
+
+	helper.SendSuccess(c, "user.profile_updated", updatedUser)
+}
+
+func (h *UserHandler) ExportData(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	export, err := h.userUsecase.ExportData(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		helper.SendSuccess(c, "user.exported", export)
+	case "zip":
+		archive, err := buildExportArchive(export)
+		if err != nil {
+			helper.SendError(c, http.StatusInternalServerError, "user.export_failed", err.Error())
+			return
+		}
+		filename := fmt.Sprintf("gogroceries-export-%d-%s.zip", userID, export.ExportedAt.Format("20060102150405"))
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Data(http.StatusOK, "application/zip", archive)
+	default:
+		helper.SendError(c, http.StatusBadRequest, "user.export_invalid_format", nil)
+	}
+}
+
+func (h *UserHandler) DeleteAccount(c *gin.Context) {
+	userID := c.MustGet("user_id").(uint)
+
+	var req domain.DeleteAccountRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(helper.BindingError(err))
+			return
 		}
+	}
+
+	if err := h.userUsecase.DeleteAccount(c.Request.Context(), userID, &req); err != nil {
+		c.Error(err)
 		return
 	}
 
-	helper.SendSuccess(c, "Profile updated successfully", updatedUser)
-}
\ No newline at end of file
+	helper.SendSuccess(c, "user.account_deleted", nil)
+}
+
+func buildExportArchive(export *domain.UserDataExport) ([]byte, error) {
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"alamat.json", export.Alamat},
+		{"transaksi.json", export.Transaksi},
+		{"toko_memberships.json", export.Memberships},
+		{"identities.json", export.Identities},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -3,57 +3,233 @@ package middleware
 import (
 	"gogroceries/domain"
 	"gogroceries/internal/helper"
+	"gogroceries/internal/logger"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware(jwtAuth helper.JWTInterface) gin.HandlerFunc {
+func AuthMiddleware(jwtAuth helper.JWTInterface, userUC domain.UserUsecase) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			helper.SendError(c, http.StatusUnauthorized, "Header Authorization needed", nil)
-			c.Abort()
+		tokenString, ok := bearerToken(c)
+		if !ok {
 			return
 		}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			helper.SendError(c, http.StatusUnauthorized, "Wrong Format header Authorization (Must: Bearer <token>)", nil)
+		if !authenticateJWT(c, jwtAuth, userUC, tokenString) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// AuthOrAPIKeyMiddleware accepts either a user JWT or a toko API key carrying
+// the given scope. API keys may be sent as "X-API-Key" or as the Bearer token.
+func AuthOrAPIKeyMiddleware(jwtAuth helper.JWTInterface, userUC domain.UserUsecase, apiKeyUC domain.ApiKeyUsecase, scope domain.TokoPermission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader("X-API-Key")
+		if rawKey == "" {
+			tokenString, ok := bearerToken(c)
+			if !ok {
+				return
+			}
+			if !strings.HasPrefix(tokenString, domain.ApiKeyPrefix+"_") {
+				if !authenticateJWT(c, jwtAuth, userUC, tokenString) {
+					return
+				}
+				c.Next()
+				return
+			}
+			rawKey = tokenString
+		}
+
+		apiKey, err := apiKeyUC.Authenticate(c.Request.Context(), rawKey)
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Warn("api key rejected", "error", err)
+			helper.SendError(c, http.StatusUnauthorized, "auth.api_key_invalid", nil)
+			c.Abort()
+			return
+		}
+		if !apiKey.HasScope(scope) {
+			helper.SendError(c, http.StatusForbidden, "auth.api_key_missing_scope", string(scope))
+			c.Abort()
+			return
+		}
+		if idToko := c.Param("id_toko"); idToko != "" && idToko != strconv.FormatUint(uint64(apiKey.IdToko), 10) {
+			helper.SendError(c, http.StatusForbidden, "auth.api_key_wrong_toko", nil)
 			c.Abort()
 			return
 		}
 
-		tokenString := parts[1]
-		claims, err := jwtAuth.ValidateToken(tokenString)
+		user, err := userUC.GetProfileById(c.Request.Context(), apiKey.IdUser)
 		if err != nil {
-			helper.SendError(c, http.StatusUnauthorized, "Token is not valid or expired", err.Error())
+			helper.SendError(c, http.StatusUnauthorized, "auth.api_key_owner_missing", nil)
+			c.Abort()
+			return
+		}
+		if user.IsSuspended() {
+			helper.SendError(c, http.StatusForbidden, "auth.suspended", nil)
 			c.Abort()
 			return
 		}
 
+		claims := &domain.JWTClaims{
+			UserID: user.ID,
+			Email:  user.Email,
+		}
 		c.Set("user_claims", claims)
 		c.Set("user_id", claims.UserID)
+		c.Set("api_key", apiKey)
+		setLogUser(c, claims.UserID)
 		c.Next()
 	}
 }
 
+// ApiKeyAllowsToko reports whether the current request may act on tokoID.
+// JWT-authenticated requests are always allowed; API keys are pinned to their toko.
+func ApiKeyAllowsToko(c *gin.Context, tokoID uint) bool {
+	keyTokoID, isApiKey := ApiKeyToko(c)
+	return !isApiKey || keyTokoID == tokoID
+}
+
+func ApiKeyToko(c *gin.Context) (uint, bool) {
+	value, exists := c.Get("api_key")
+	if !exists {
+		return 0, false
+	}
+	apiKey, ok := value.(*domain.ApiKey)
+	if !ok {
+		return 0, false
+	}
+	return apiKey.IdToko, true
+}
+
+func bearerToken(c *gin.Context) (string, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		helper.SendError(c, http.StatusUnauthorized, "auth.header_missing", nil)
+		c.Abort()
+		return "", false
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		helper.SendError(c, http.StatusUnauthorized, "auth.header_invalid", nil)
+		c.Abort()
+		return "", false
+	}
+
+	return parts[1], true
+}
+
+func authenticateJWT(c *gin.Context, jwtAuth helper.JWTInterface, userUC domain.UserUsecase, tokenString string) bool {
+	claims, err := jwtAuth.ValidateToken(tokenString)
+	if err != nil {
+		helper.SendError(c, http.StatusUnauthorized, "auth.token_invalid", err.Error())
+		c.Abort()
+		return false
+	}
+
+	user, err := userUC.GetProfileById(c.Request.Context(), claims.UserID)
+	if err != nil {
+		helper.SendError(c, http.StatusUnauthorized, "auth.user_missing", nil)
+		c.Abort()
+		return false
+	}
+	if user.IsSuspended() {
+		helper.SendError(c, http.StatusForbidden, "auth.suspended", nil)
+		c.Abort()
+		return false
+	}
+
+	// Roles granted or revoked after the token was issued apply right away.
+	roles, err := userUC.GetRoles(c.Request.Context(), user)
+	if err != nil {
+		c.Error(err)
+		c.Abort()
+		return false
+	}
+	claims.IsAdmin = user.IsAdmin
+	claims.Roles = roles
+
+	c.Set("user_claims", claims)
+	c.Set("user_id", claims.UserID)
+	setLogUser(c, claims.UserID)
+	return true
+}
+
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userClaims, exists := c.Get("user_claims")
 		if !exists {
-			helper.SendError(c, http.StatusForbidden, "User claims not found in context", nil)
+			helper.SendError(c, http.StatusForbidden, "auth.claims_missing", nil)
+			c.Abort()
+			return
+		}
+
+		claims, ok := userClaims.(*domain.JWTClaims)
+		if !ok || !claims.HasRole(domain.RoleAdmin) {
+			helper.SendError(c, http.StatusForbidden, "auth.admin_only", nil)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func RequirePermission(permissions ...domain.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userClaims, exists := c.Get("user_claims")
+		if !exists {
+			helper.SendError(c, http.StatusForbidden, "auth.claims_missing", nil)
 			c.Abort()
 			return
 		}
 
 		claims, ok := userClaims.(*domain.JWTClaims)
-		if !ok || !claims.IsAdmin {
-			helper.SendError(c, http.StatusForbidden, "Access denied: Only admin", nil)
+		if !ok {
+			helper.SendError(c, http.StatusForbidden, "auth.claims_missing", nil)
 			c.Abort()
 			return
 		}
+
+		for _, permission := range permissions {
+			if !claims.HasPermission(permission) {
+				helper.SendError(c, http.StatusForbidden, "auth.permission_missing", string(permission))
+				c.Abort()
+				return
+			}
+		}
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+
+// RequireAnyPermission passes when the user has at least one of permissions.
+func RequireAnyPermission(permissions ...domain.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userClaims, exists := c.Get("user_claims")
+		if !exists {
+			helper.SendError(c, http.StatusForbidden, "auth.claims_missing", nil)
+			c.Abort()
+			return
+		}
+
+		claims, ok := userClaims.(*domain.JWTClaims)
+		if !ok {
+			helper.SendError(c, http.StatusForbidden, "auth.claims_missing", nil)
+			c.Abort()
+			return
+		}
+
+		for _, permission := range permissions {
+			if claims.HasPermission(permission) {
+				c.Next()
+				return
+			}
+		}
+		helper.SendError(c, http.StatusForbidden, "auth.permission_missing", permissions)
+		c.Abort()
+	}
+}
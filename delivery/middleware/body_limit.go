@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"gogroceries/internal/helper"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimit caps the request body at maxBytes; reading past it fails with
+// *http.MaxBytesError.
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if maxBytes > 0 && c.Request.Body != nil {
+			if c.Request.ContentLength > maxBytes {
+				helper.SendError(c, http.StatusRequestEntityTooLarge, "upload.too_large", nil)
+				c.Abort()
+				return
+			}
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		}
+		c.Next()
+	}
+}
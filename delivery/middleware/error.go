@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"gogroceries/domain"
+	"gogroceries/internal/helper"
+	"gogroceries/internal/i18n"
+	"gogroceries/internal/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statusClientClosedRequest is the nginx convention for requests abandoned by
+// the client; nothing is sent, it only shows up in the request log.
+const statusClientClosedRequest = 499
+
+// ErrorHandler renders errors attached with c.Error as a domain.Response.
+// Unknown errors are logged and reported as INTERNAL_ERROR without details;
+// errors caused by the request deadline become SERVICE_UNAVAILABLE.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+
+		log := logger.FromContext(c.Request.Context())
+
+		var domainErr *domain.Error
+		if !errors.As(err, &domainErr) || domainErr.Code == domain.CodeInternal {
+			switch {
+			case errors.Is(err, context.DeadlineExceeded):
+				log.Warn("request timed out", "error", err)
+				domainErr = &domain.Error{Code: domain.CodeUnavailable, Message: "error.timeout", Err: err}
+			case errors.Is(err, context.Canceled):
+				log.Info("request canceled by client", "error", err)
+				c.AbortWithStatus(statusClientClosedRequest)
+				return
+			default:
+				log.Error("request failed", "error", err)
+				if domainErr == nil {
+					domainErr = &domain.Error{Code: domain.CodeInternal, Message: "error.internal", Err: err}
+				}
+			}
+		}
+
+		lang := helper.Lang(c)
+		response := domain.Response{
+			Status:  false,
+			Message: i18n.Format(lang, domainErr.Message, domainErr.Params),
+			Code:    domainErr.Code,
+		}
+		var fields map[string]string
+		if len(domainErr.Fields) > 0 {
+			fields = make(map[string]string, len(domainErr.Fields))
+			for field, key := range domainErr.Fields {
+				fields[field] = i18n.Format(lang, key, domainErr.Params)
+			}
+		} else if domainErr.Code == domain.CodeValidation {
+			fields = helper.ValidationFields(c, domainErr.Err)
+		}
+		if len(fields) > 0 {
+			response.Errors = fields
+		}
+
+		c.JSON(helper.StatusForCode(domainErr.Code), response)
+	}
+}
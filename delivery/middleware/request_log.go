@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"gogroceries/internal/helper"
+	"gogroceries/internal/logger"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// Client supplied IDs are kept only when they look like an ID, so they cannot
+// be used to inject arbitrary text into the logs.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestLogger assigns every request an X-Request-ID (reusing a valid one
+// sent by the client), stores a logger carrying the ID and route in the
+// request context and writes one access log line when the request is done.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		log := slog.Default().With(
+			"request_id", requestID,
+			"method", c.Request.Method,
+			"route", route,
+		)
+		if sc := trace.SpanContextFromContext(c.Request.Context()); sc.IsValid() {
+			log = log.With("trace_id", sc.TraceID().String())
+		}
+		c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), log))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		size := c.Writer.Size()
+		if size < 0 {
+			size = 0
+		}
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		logger.FromContext(c.Request.Context()).Log(c.Request.Context(), level, "request",
+			"path", c.Request.URL.Path,
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"bytes", size,
+			"client_ip", c.ClientIP(),
+		)
+	}
+}
+
+// Recovery turns a panic into a 500 response and logs it with the stack
+// through the request logger.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered any) {
+		logger.FromContext(c.Request.Context()).Error("panic recovered",
+			"panic", recovered,
+			"stack", string(debug.Stack()),
+		)
+		helper.SendError(c, http.StatusInternalServerError, "error.internal", nil)
+		c.Abort()
+	})
+}
+
+// setLogUser adds the authenticated user to the request logger so every
+// later log line of the request carries it.
+func setLogUser(c *gin.Context, userID uint) {
+	c.Request = c.Request.WithContext(logger.With(c.Request.Context(), "user_id", userID))
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "context"
+
+type AdminUsecase interface {
+	ListUsers(ctx context.Context, filter UserFilter, page PageRequest) (*Page[User], error)
+	GetUser(ctx context.Context, id uint) (*User, error)
+	SuspendUser(ctx context.Context, id uint, adminID uint) (*User, error)
+	ReactivateUser(ctx context.Context, id uint) (*User, error)
+	DeleteUser(ctx context.Context, id uint, adminID uint) error
+	CreateAdmin(ctx context.Context, req *CreateAdminRequest) (*User, error)
+	PromoteAdmin(ctx context.Context, identifier string) (*User, error)
+}
+
+type CreateAdminRequest struct {
+	Nama      string `json:"nama" binding:"required"`
+	Email     string `json:"email" binding:"required,email"`
+	NoTelp    string `json:"no_telp" binding:"required"`
+	KataSandi string `json:"kata_sandi" binding:"required,min=6"`
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"time"
 
 	"gorm.io/gorm"
@@ -20,20 +21,20 @@ type Alamat struct {
 }
 
 type AlamatRepository interface { 
-	FindByIDAndUserID(id uint, userID uint) (*Alamat, error)
-	FindAllByUserID(userID uint, filter AlamatFilter, limit, offset int) ([]Alamat, int64, error) 
-	Create(alamat *Alamat) error 
-	Update(alamat *Alamat) error 
-	Delete(id uint, userID uint) error
-	FindByID(id uint) (*Alamat, error)
+	FindByIDAndUserID(ctx context.Context, id uint, userID uint) (*Alamat, error)
+	FindAllByUserID(ctx context.Context, userID uint, filter AlamatFilter, page PageRequest) ([]Alamat, *PageInfo, error) 
+	Create(ctx context.Context, alamat *Alamat) error 
+	Update(ctx context.Context, alamat *Alamat) error 
+	Delete(ctx context.Context, id uint, userID uint) error
+	FindByID(ctx context.Context, id uint) (*Alamat, error)
 }
 
 type AlamatUsecase interface { 
-	CreateAlamat(req *CreateAlamatRequest, userID uint) (*Alamat, error)
-	GetAllAlamatUser(userID uint, filter AlamatFilter, page, limit int) ([]Alamat, *PaginationResponse, error)
-	GetAlamatByID(id uint, userID uint) (*Alamat, error)
-	UpdateAlamat(id uint, req *UpdateAlamatRequest, userID uint) (*Alamat, error)
-	DeleteAlamat(id uint, userID uint) error
+	CreateAlamat(ctx context.Context, req *CreateAlamatRequest, userID uint) (*Alamat, error)
+	GetAllAlamatUser(ctx context.Context, userID uint, filter AlamatFilter, page PageRequest) (*Page[Alamat], error)
+	GetAlamatByID(ctx context.Context, id uint, userID uint) (*Alamat, error)
+	UpdateAlamat(ctx context.Context, id uint, req *UpdateAlamatRequest, userID uint) (*Alamat, error)
+	DeleteAlamat(ctx context.Context, id uint, userID uint) error
 }
 
 type CreateAlamatRequest struct {
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const ApiKeyPrefix = "gg"
+
+var ApiKeyScopes = []TokoPermission{
+	TokoPermissionProdukWrite,
+	TokoPermissionOrdersRead,
+	TokoPermissionOrdersWrite,
+}
+
+func IsValidApiKeyScope(scope TokoPermission) bool {
+	for _, s := range ApiKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type ApiKey struct {
+	ID         uint             `gorm:"primaryKey" json:"id"`
+	IdToko     uint             `gorm:"not null;index" json:"toko_id"`
+	IdUser     uint             `gorm:"not null;index" json:"user_id"`
+	Nama       string           `gorm:"size:255;not null" json:"nama"`
+	Prefix     string           `gorm:"size:32;not null;uniqueIndex" json:"prefix"`
+	KeyHash    string           `gorm:"size:64;not null" json:"-"`
+	Scopes     []TokoPermission `gorm:"type:text;serializer:json" json:"scopes"`
+	ExpiresAt  *time.Time       `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time       `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time       `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+func (k *ApiKey) HasScope(scope TokoPermission) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (k *ApiKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+type ApiKeyRepository interface {
+	Create(ctx context.Context, key *ApiKey) error
+	Update(ctx context.Context, key *ApiKey) error
+	FindByID(ctx context.Context, id uint) (*ApiKey, error)
+	FindByPrefix(ctx context.Context, prefix string) (*ApiKey, error)
+	FindAllByTokoID(ctx context.Context, tokoID uint) ([]ApiKey, error)
+	FindAllByTokoIDPaged(ctx context.Context, tokoID uint, page PageRequest) ([]ApiKey, *PageInfo, error)
+	TouchLastUsed(ctx context.Context, id uint, at time.Time) error
+}
+
+type ApiKeyUsecase interface {
+	GetMyApiKeys(ctx context.Context, tokoID, userID uint) ([]ApiKey, error)
+	ListMyApiKeys(ctx context.Context, tokoID, userID uint, page PageRequest) (*Page[ApiKey], error)
+	CreateApiKey(ctx context.Context, req *CreateApiKeyRequest, userID uint) (*CreateApiKeyResponse, error)
+	RevokeApiKey(ctx context.Context, id, userID uint) error
+	Authenticate(ctx context.Context, rawKey string) (*ApiKey, error)
+}
+
+type CreateApiKeyRequest struct {
+	IdToko        uint             `json:"toko_id"`
+	Nama          string           `json:"nama" binding:"required"`
+	Scopes        []TokoPermission `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays int              `json:"expires_in_days" binding:"omitempty,min=1"`
+}
+
+type CreateApiKeyResponse struct {
+	ApiKey *ApiKey `json:"api_key"`
+	Key    string  `json:"key"`
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"context"
+
+	"github.com/golang-jwt/jwt/v5"
+)
 
 type RegisterRequest struct {
 	Nama         string `json:"nama" binding:"required"`
@@ -37,10 +41,35 @@ type JWTClaims struct {
 	UserID  uint   `json:"user_id"`
 	Email   string `json:"email"`
 	IsAdmin bool   `json:"is_admin"`
+	Roles   []Role `json:"roles,omitempty"`
 	jwt.RegisteredClaims
 }
 
+func (c *JWTClaims) HasRole(role Role) bool {
+	if role == RoleAdmin && c.IsAdmin {
+		return true
+	}
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *JWTClaims) HasPermission(permission Permission) bool {
+	if c.HasRole(RoleAdmin) {
+		return true
+	}
+	for _, r := range c.Roles {
+		if RoleHasPermission(r, permission) {
+			return true
+		}
+	}
+	return false
+}
+
 type AuthUsecase interface {
-	Register(req *RegisterRequest) (*User, error)
-	Login(req *LoginRequest) (string, *User, error)
+	Register(ctx context.Context, req *RegisterRequest) (*User, error)
+	Login(ctx context.Context, req *LoginRequest) (string, *User, error)
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"time"
 
 	"gorm.io/gorm"
@@ -8,33 +9,89 @@ import (
 
 type Category struct {
 	ID           uint           `gorm:"primaryKey" json:"id"`
-	NamaCategory string         `gorm:"size:255;not null;uniqueIndex" json:"nama_category"` 
-	Produk       []Produk       `gorm:"foreignKey:IdCategory" json:"-"` 
+	ParentID     *uint          `gorm:"index;uniqueIndex:idx_categories_parent_nama,priority:1,where:deleted_at IS NULL" json:"parent_id"`
+	NamaCategory string         `gorm:"size:255;not null;uniqueIndex:idx_categories_parent_nama,priority:2,where:deleted_at IS NULL;uniqueIndex:idx_categories_root_nama,where:parent_id IS NULL AND deleted_at IS NULL" json:"nama_category"`
+	Slug         string         `gorm:"size:255;not null;uniqueIndex:idx_categories_slug,where:deleted_at IS NULL" json:"slug"`
+	SortOrder    int            `gorm:"not null;default:0" json:"sort_order"`
+	IconURL      string         `gorm:"size:255" json:"icon_url"`
+	Children     []Category     `gorm:"foreignKey:ParentID" json:"children,omitempty"`
+	Produk       []Produk       `gorm:"foreignKey:IdCategory" json:"-"`
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 type CategoryRepository interface {
-	FindByID(id uint) (*Category, error)
-	FindAll() ([]Category, error)
-	Create(category *Category) error
-	Update(category *Category) error
-	Delete(id uint) error
+	FindByID(ctx context.Context, id uint) (*Category, error)
+	// LockByID is FindByID that also locks the row until the transaction ends.
+	LockByID(ctx context.Context, id uint) (*Category, error)
+	FindBySlug(ctx context.Context, slug string) (*Category, error)
+	FindAll(ctx context.Context) ([]Category, error)
+	FindAllPaged(ctx context.Context, page PageRequest) ([]Category, *PageInfo, error)
+	// SubtreeIDs returns the id of the category and of all its descendants.
+	SubtreeIDs(ctx context.Context, id uint) ([]uint, error)
+	CountChildren(ctx context.Context, id uint) (int64, error)
+	CountProduk(ctx context.Context, id uint) (int64, error)
+	// ReassignProduk and ReassignChildren move everything that references
+	// fromID to toID, soft-deleted rows included, and return how many moved.
+	ReassignProduk(ctx context.Context, fromID, toID uint) (int64, error)
+	ReassignChildren(ctx context.Context, fromID, toID uint) (int64, error)
+	Create(ctx context.Context, category *Category) error
+	Update(ctx context.Context, category *Category) error
+	Delete(ctx context.Context, id uint) error
 }
 
 type CategoryUsecase interface {
-	CreateCategory(req *CreateCategoryRequest) (*Category, error)
-	GetAllCategories() ([]Category, error)
-	GetCategoryByID(id uint) (*Category, error)
-	UpdateCategory(id uint, req *UpdateCategoryRequest) (*Category, error)
-	DeleteCategory(id uint) error
+	CreateCategory(ctx context.Context, req *CreateCategoryRequest) (*Category, error)
+	GetAllCategories(ctx context.Context) ([]Category, error)
+	ListCategories(ctx context.Context, page PageRequest) (*Page[Category], error)
+	GetCategoryTree(ctx context.Context) ([]Category, error)
+	GetCategoryByID(ctx context.Context, id uint) (*Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*Category, error)
+	UpdateCategory(ctx context.Context, id uint, req *UpdateCategoryRequest) (*Category, error)
+	GetCategoryUsage(ctx context.Context, id uint) (*CategoryUsage, error)
+	DeleteCategory(ctx context.Context, id uint) error
+	MergeCategory(ctx context.Context, id uint, req *MergeCategoryRequest) (*MergeCategoryResult, error)
 }
 
+// CategoryUsage counts what still references a category. Only an unused
+// category can be deleted; otherwise merge it into another one.
+type CategoryUsage struct {
+	Produk   int64 `json:"produk"`
+	Children int64 `json:"children"`
+}
+
+// MergeCategoryRequest moves the products and sub-categories of a category to
+// TargetID and then deletes it.
+type MergeCategoryRequest struct {
+	TargetID uint `json:"target_id" binding:"required"`
+}
+
+type MergeCategoryResult struct {
+	Target        *Category `json:"target"`
+	MovedProduk   int64     `json:"moved_produk"`
+	MovedChildren int64     `json:"moved_children"`
+}
+
+// CreateCategoryRequest creates a top-level category, or a sub-category when
+// ParentID is set. Slug is generated from the name when empty.
 type CreateCategoryRequest struct {
-	NamaCategory string `json:"nama_category" binding:"required"` 
+	NamaCategory string `json:"nama_category" binding:"required"`
+	Slug         string `json:"slug" binding:"omitempty,max=255"`
+	ParentID     *uint  `json:"parent_id"`
+	SortOrder    int    `json:"sort_order"`
+	IconURL      string `json:"icon_url" binding:"omitempty,url,max=255"`
 }
 
+// UpdateCategoryRequest updates a category. Omitted fields keep their current
+// value, and an empty Slug keeps the current one, so renaming a category does
+// not break its URLs. DetachParent moves it to the top level and an empty
+// IconURL removes the icon.
 type UpdateCategoryRequest struct {
-	NamaCategory string `json:"nama_category" binding:"required"` 
-}
\ No newline at end of file
+	NamaCategory *string `json:"nama_category" binding:"omitempty,min=1,max=255"`
+	Slug         string  `json:"slug" binding:"omitempty,max=255"`
+	ParentID     *uint   `json:"parent_id"`
+	DetachParent bool    `json:"detach_parent"`
+	SortOrder    *int    `json:"sort_order"`
+	IconURL      *string `json:"icon_url" binding:"omitempty,max=255"`
+}
@@ -9,7 +9,7 @@ import (
 type DetailTrx struct {
 	ID          uint           `gorm:"primaryKey" json:"id"`
 	IdTrx       uint           `gorm:"not null;index" json:"-"`
-	IdProduk    uint           `gorm:"not null" json:"-"` 
+	IdProduk    uint           `gorm:"not null;index" json:"-"` 
 	IdToko      uint           `gorm:"not null" json:"-"`
 	Kuantitas   int            `gorm:"not null" json:"kuantitas"`   
 	HargaTotal  int            `gorm:"not null" json:"harga_total"` 
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+type ErrorCode string
+
+const (
+	CodeNotFound          ErrorCode = "NOT_FOUND"
+	CodeForbidden         ErrorCode = "FORBIDDEN"
+	CodeConflict          ErrorCode = "CONFLICT"
+	CodeInsufficientStock ErrorCode = "INSUFFICIENT_STOCK"
+	CodeValidation        ErrorCode = "VALIDATION_FAILED"
+	CodeUnauthorized      ErrorCode = "UNAUTHORIZED"
+	CodeInternal          ErrorCode = "INTERNAL_ERROR"
+	CodeUnavailable       ErrorCode = "SERVICE_UNAVAILABLE"
+)
+
+// Error is the error type returned by usecases. Message is an i18n catalog
+// key, rendered with Params by the error middleware; Fields values are
+// catalog keys too. Handlers never inspect the message; the error middleware
+// maps Code to an HTTP status.
+type Error struct {
+	Code    ErrorCode
+	Message string
+	Params  map[string]interface{}
+	Fields  map[string]string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if len(e.Params) > 0 {
+		msg = fmt.Sprintf("%s %v", msg, e.Params)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is lets errors.Is(err, domain.ErrNotFound) match any error with the same code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Message == "" && t.Code == e.Code
+}
+
+var (
+	ErrNotFound          = &Error{Code: CodeNotFound}
+	ErrForbidden         = &Error{Code: CodeForbidden}
+	ErrConflict          = &Error{Code: CodeConflict}
+	ErrInsufficientStock = &Error{Code: CodeInsufficientStock}
+	ErrValidation        = &Error{Code: CodeValidation}
+	ErrUnauthorized      = &Error{Code: CodeUnauthorized}
+)
+
+// The constructors take a catalog key followed by alternating parameter
+// names and values, like slog: NewNotFoundError("trx.produk_not_found", "id", 7).
+func NewNotFoundError(key string, params ...interface{}) error {
+	return &Error{Code: CodeNotFound, Message: key, Params: paramsOf(params)}
+}
+
+func NewForbiddenError(key string, params ...interface{}) error {
+	return &Error{Code: CodeForbidden, Message: key, Params: paramsOf(params)}
+}
+
+func NewConflictError(key string, params ...interface{}) error {
+	return &Error{Code: CodeConflict, Message: key, Params: paramsOf(params)}
+}
+
+func NewInsufficientStockError(key string, params ...interface{}) error {
+	return &Error{Code: CodeInsufficientStock, Message: key, Params: paramsOf(params)}
+}
+
+func NewUnauthorizedError(key string, params ...interface{}) error {
+	return &Error{Code: CodeUnauthorized, Message: key, Params: paramsOf(params)}
+}
+
+func NewValidationError(key string, fields map[string]string, params ...interface{}) error {
+	return &Error{Code: CodeValidation, Message: key, Fields: fields, Params: paramsOf(params)}
+}
+
+// NewInternalError wraps an unexpected failure; only message reaches the client.
+func NewInternalError(message string, err error) error {
+	return &Error{Code: CodeInternal, Message: message, Err: err}
+}
+
+func ErrorCodeOf(err error) ErrorCode {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+	return CodeInternal
+}
+
+func paramsOf(kv []interface{}) map[string]interface{} {
+	if len(kv) == 0 {
+		return nil
+	}
+	params := make(map[string]interface{}, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		params[fmt.Sprint(kv[i])] = kv[i+1]
+	}
+	return params
+}
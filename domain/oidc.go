@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type OIDCIdentity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type OIDCProvider interface {
+	Name() string
+	AuthCodeURL(state, nonce, codeVerifier string) string
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*OIDCIdentity, error)
+}
+
+type UserIdentity struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	IdUser    uint      `gorm:"not null;index" json:"user_id"`
+	Provider  string    `gorm:"size:50;not null;uniqueIndex:idx_identity_subject" json:"provider"`
+	Subject   string    `gorm:"size:255;not null;uniqueIndex:idx_identity_subject" json:"subject"`
+	Email     string    `gorm:"size:255" json:"email"`
+	User      *User     `gorm:"foreignKey:IdUser;references:ID" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type OAuthState struct {
+	ID           uint      `gorm:"primaryKey"`
+	State        string    `gorm:"size:255;not null;uniqueIndex"`
+	Provider     string    `gorm:"size:50;not null"`
+	Nonce        string    `gorm:"size:255;not null"`
+	CodeVerifier string    `gorm:"size:255;not null"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+}
+
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *UserIdentity) error
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*UserIdentity, error)
+	FindAllByUserID(ctx context.Context, userID uint) ([]UserIdentity, error)
+}
+
+type OAuthStateRepository interface {
+	Create(ctx context.Context, state *OAuthState) error
+	Consume(ctx context.Context, state string) (*OAuthState, error)
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+type OIDCUsecase interface {
+	LoginURL(ctx context.Context, provider string) (string, error)
+	Callback(ctx context.Context, provider, state, code string) (string, *User, error)
+	CleanupExpiredStates(ctx context.Context) error
+}
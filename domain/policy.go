@@ -0,0 +1,158 @@
+package domain
+
+import "context"
+
+type Actor struct {
+	UserID      uint
+	Roles       []Role
+	Memberships []TokoMember
+}
+
+type ActorProvider interface {
+	Actor(ctx context.Context, userID uint) (*Actor, error)
+}
+
+func (a *Actor) HasRole(role Role) bool {
+	for _, r := range a.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Actor) HasPermission(permission Permission) bool {
+	for _, r := range a.Roles {
+		if RoleHasPermission(r, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Actor) Membership(tokoID uint) *TokoMember {
+	for i := range a.Memberships {
+		if a.Memberships[i].IdToko == tokoID {
+			return &a.Memberships[i]
+		}
+	}
+	return nil
+}
+
+func (a *Actor) TokoIDsWith(permission TokoPermission) []uint {
+	var ids []uint
+	for _, m := range a.Memberships {
+		if m.Can(permission) {
+			ids = append(ids, m.IdToko)
+		}
+	}
+	return ids
+}
+
+func (a *Actor) canInToko(tokoID uint, permission TokoPermission) bool {
+	return a.Membership(tokoID).Can(permission)
+}
+
+// Every registered user is a seller, so reading any order is a platform
+// permission that seller roles do not get; sellers read orders through their
+// toko membership.
+func (a *Actor) canReadAllOrders() bool {
+	return a.HasPermission(PermissionOrderRead)
+}
+
+func CanCreateProdukInToko(a *Actor, tokoID uint) bool {
+	return a != nil && a.canInToko(tokoID, TokoPermissionProdukWrite)
+}
+
+func CanEditProduk(a *Actor, produk *Produk) bool {
+	if a == nil || produk == nil {
+		return false
+	}
+	return a.HasRole(RoleAdmin) || a.canInToko(produk.IdToko, TokoPermissionProdukWrite)
+}
+
+func CanEditToko(a *Actor, toko *Toko) bool {
+	if a == nil || toko == nil {
+		return false
+	}
+	return a.HasRole(RoleAdmin) || a.canInToko(toko.ID, TokoPermissionTokoWrite)
+}
+
+func CanViewTokoMembers(a *Actor, tokoID uint) bool {
+	return a != nil && (a.HasRole(RoleAdmin) || a.Membership(tokoID) != nil)
+}
+
+func CanManageTokoMembers(a *Actor, tokoID uint) bool {
+	return a != nil && a.canInToko(tokoID, TokoPermissionMembersManage)
+}
+
+// Only an owner may hand out or take away the manager role; nobody can
+// become or demote an owner through membership management.
+func CanAssignTokoRole(a *Actor, tokoID uint, from, to TokoRole) bool {
+	if !CanManageTokoMembers(a, tokoID) {
+		return false
+	}
+	if from == TokoRoleOwner || to == TokoRoleOwner {
+		return false
+	}
+	if from == TokoRoleManager || to == TokoRoleManager {
+		return a.Membership(tokoID).Role == TokoRoleOwner
+	}
+	return true
+}
+
+func CanManageAlamat(a *Actor, alamat *Alamat) bool {
+	return a != nil && alamat != nil && alamat.IdUser == a.UserID
+}
+
+func CanViewTrx(a *Actor, trx *Trx) bool {
+	if a == nil || trx == nil {
+		return false
+	}
+	if trx.IdUser == a.UserID || a.canReadAllOrders() {
+		return true
+	}
+	for _, d := range trx.DetailTrx {
+		if a.canInToko(d.IdToko, TokoPermissionOrdersRead) {
+			return true
+		}
+	}
+	return false
+}
+
+func CanViewTokoOrders(a *Actor, tokoID uint) bool {
+	return a != nil && (a.canReadAllOrders() || a.canInToko(tokoID, TokoPermissionOrdersRead))
+}
+
+func CanListAllOrders(a *Actor) bool {
+	return a != nil && a.canReadAllOrders()
+}
+
+// CanUpdateOrderStatus covers status changes made by platform staff rather
+// than by a toko: order:manage allows every transition, order:deliver only
+// marks a shipped order as completed.
+func CanUpdateOrderStatus(a *Actor, trx *Trx, to string) bool {
+	if a == nil || trx == nil {
+		return false
+	}
+	if a.HasPermission(PermissionOrderManage) {
+		return true
+	}
+	return a.HasPermission(PermissionOrderDeliver) && trx.Status == TrxStatusShipped && to == TrxStatusCompleted
+}
+
+func CanUpdateTokoOrder(a *Actor, tokoID uint, trx *Trx) bool {
+	if a == nil || trx == nil || !a.canInToko(tokoID, TokoPermissionOrdersWrite) {
+		return false
+	}
+	for _, d := range trx.DetailTrx {
+		if d.IdToko != tokoID {
+			return false
+		}
+	}
+	return true
+}
+
+func CanManageApiKeys(a *Actor, tokoID uint) bool {
+	return a != nil && a.canInToko(tokoID, TokoPermissionApiKeysManage)
+}
@@ -0,0 +1,259 @@
+package domain
+
+import "testing"
+
+const (
+	ownerID uint = iota + 1
+	otherSellerID
+	packerID
+	managerID
+	adminID
+	customerServiceID
+	courierID
+	buyerID
+)
+
+const (
+	tokoA uint = 10
+	tokoB uint = 20
+)
+
+var (
+	owner = &Actor{
+		UserID:      ownerID,
+		Roles:       []Role{RoleSeller},
+		Memberships: []TokoMember{{IdToko: tokoA, IdUser: ownerID, Role: TokoRoleOwner}},
+	}
+	otherSeller = &Actor{
+		UserID:      otherSellerID,
+		Roles:       []Role{RoleSeller},
+		Memberships: []TokoMember{{IdToko: tokoB, IdUser: otherSellerID, Role: TokoRoleOwner}},
+	}
+	packer = &Actor{
+		UserID:      packerID,
+		Roles:       []Role{RoleSeller},
+		Memberships: []TokoMember{{IdToko: tokoA, IdUser: packerID, Role: TokoRolePacker}},
+	}
+	manager = &Actor{
+		UserID:      managerID,
+		Roles:       []Role{RoleSeller},
+		Memberships: []TokoMember{{IdToko: tokoA, IdUser: managerID, Role: TokoRoleManager}},
+	}
+	admin           = &Actor{UserID: adminID, Roles: []Role{RoleAdmin}}
+	customerService = &Actor{UserID: customerServiceID, Roles: []Role{RoleCustomerService}}
+	courier         = &Actor{UserID: courierID, Roles: []Role{RoleCourier}}
+	buyer           = &Actor{UserID: buyerID, Roles: []Role{RoleSeller}}
+)
+
+func TestCanEditProduk(t *testing.T) {
+	produk := &Produk{IdToko: tokoA}
+	tests := []struct {
+		name  string
+		actor *Actor
+		want  bool
+	}{
+		{"owner", owner, true},
+		{"manager", manager, true},
+		{"packer", packer, false},
+		{"another seller", otherSeller, false},
+		{"admin", admin, true},
+		{"customer service", customerService, false},
+		{"nil actor", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanEditProduk(tt.actor, produk); got != tt.want {
+				t.Errorf("CanEditProduk() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanManageAlamat(t *testing.T) {
+	alamat := &Alamat{IdUser: ownerID}
+	tests := []struct {
+		name  string
+		actor *Actor
+		want  bool
+	}{
+		{"owner", owner, true},
+		{"another seller", otherSeller, false},
+		{"staff", manager, false},
+		{"admin", admin, false},
+		{"nil actor", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanManageAlamat(tt.actor, alamat); got != tt.want {
+				t.Errorf("CanManageAlamat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanEditToko(t *testing.T) {
+	toko := &Toko{ID: tokoA, IdUser: ownerID}
+	tests := []struct {
+		name  string
+		actor *Actor
+		want  bool
+	}{
+		{"owner", owner, true},
+		{"manager", manager, false},
+		{"packer", packer, false},
+		{"another seller", otherSeller, false},
+		{"admin", admin, true},
+		{"nil actor", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanEditToko(tt.actor, toko); got != tt.want {
+				t.Errorf("CanEditToko() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanManageApiKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		actor *Actor
+		want  bool
+	}{
+		{"owner", owner, true},
+		{"manager", manager, true},
+		{"packer", packer, false},
+		{"another seller", otherSeller, false},
+		{"nil actor", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanManageApiKeys(tt.actor, tokoA); got != tt.want {
+				t.Errorf("CanManageApiKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanViewTrx(t *testing.T) {
+	trx := &Trx{IdUser: buyerID, DetailTrx: []DetailTrx{{IdToko: tokoA}}}
+	tests := []struct {
+		name  string
+		actor *Actor
+		want  bool
+	}{
+		{"buyer", buyer, true},
+		{"owner", owner, true},
+		{"packer", packer, true},
+		{"another seller", otherSeller, false},
+		{"admin", admin, true},
+		{"customer service", customerService, true},
+		{"courier", courier, true},
+		{"nil actor", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanViewTrx(tt.actor, trx); got != tt.want {
+				t.Errorf("CanViewTrx() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanViewTokoOrders(t *testing.T) {
+	tests := []struct {
+		name  string
+		actor *Actor
+		want  bool
+	}{
+		{"owner", owner, true},
+		{"packer", packer, true},
+		{"another seller", otherSeller, false},
+		{"seller without toko", buyer, false},
+		{"admin", admin, true},
+		{"customer service", customerService, true},
+		{"nil actor", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanViewTokoOrders(tt.actor, tokoA); got != tt.want {
+				t.Errorf("CanViewTokoOrders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanUpdateTokoOrder(t *testing.T) {
+	own := &Trx{IdUser: buyerID, DetailTrx: []DetailTrx{{IdToko: tokoA}}}
+	mixed := &Trx{IdUser: buyerID, DetailTrx: []DetailTrx{{IdToko: tokoA}, {IdToko: tokoB}}}
+	tests := []struct {
+		name  string
+		actor *Actor
+		trx   *Trx
+		want  bool
+	}{
+		{"owner", owner, own, true},
+		{"packer", packer, own, true},
+		{"owner, order spans another toko", owner, mixed, false},
+		{"another seller", otherSeller, own, false},
+		{"admin", admin, own, false},
+		{"customer service", customerService, own, false},
+		{"nil trx", owner, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanUpdateTokoOrder(tt.actor, tokoA, tt.trx); got != tt.want {
+				t.Errorf("CanUpdateTokoOrder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanListAllOrders(t *testing.T) {
+	tests := []struct {
+		name  string
+		actor *Actor
+		want  bool
+	}{
+		{"owner", owner, false},
+		{"packer", packer, false},
+		{"admin", admin, true},
+		{"customer service", customerService, true},
+		{"courier", courier, true},
+		{"nil actor", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanListAllOrders(tt.actor); got != tt.want {
+				t.Errorf("CanListAllOrders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanUpdateOrderStatus(t *testing.T) {
+	shipped := &Trx{IdUser: buyerID, Status: TrxStatusShipped, DetailTrx: []DetailTrx{{IdToko: tokoA}}}
+	pending := &Trx{IdUser: buyerID, Status: TrxStatusPending, DetailTrx: []DetailTrx{{IdToko: tokoA}}}
+	tests := []struct {
+		name  string
+		actor *Actor
+		trx   *Trx
+		to    string
+		want  bool
+	}{
+		{"admin cancels", admin, pending, TrxStatusCancelled, true},
+		{"courier delivers", courier, shipped, TrxStatusCompleted, true},
+		{"courier cancels", courier, pending, TrxStatusCancelled, false},
+		{"courier completes unshipped order", courier, pending, TrxStatusCompleted, false},
+		{"customer service", customerService, shipped, TrxStatusCompleted, false},
+		{"toko owner", owner, shipped, TrxStatusCompleted, false},
+		{"nil trx", admin, nil, TrxStatusCancelled, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanUpdateOrderStatus(tt.actor, tt.trx, tt.to); got != tt.want {
+				t.Errorf("CanUpdateOrderStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
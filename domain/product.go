@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"time"
 
 	"gorm.io/gorm"
@@ -20,6 +21,7 @@ type Produk struct {
 	Toko          *Toko          `gorm:"foreignKey:IdToko;references:ID" json:"toko"`        
 	Category      *Category      `gorm:"foreignKey:IdCategory;references:ID" json:"category"` 
 	FotoProduk    []FotoProduk   `gorm:"foreignKey:IdProduk;constraint:OnDelete:CASCADE;" json:"photos,omitempty"` 
+	Highlight     *ProdukHighlight `gorm:"-" json:"highlight,omitempty"`
 
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
@@ -27,23 +29,26 @@ type Produk struct {
 }
 
 type ProdukRepository interface {
-	Create(produk *Produk, fotoUrls []string) (*Produk, error)
-	FindAll(filter ProdukFilter, limit, offset int) ([]Produk, int64, error)
-	FindByID(id uint) (*Produk, error)
-	FindBySlug(slug string) (*Produk, error)
-	FindByIDs(ids []uint) ([]Produk, error)
-	Update(produk *Produk) error
-	Delete(id uint) error
-	FindFotoByProdukID(produkID uint) ([]FotoProduk, error)
-	UpdateStok(tx *gorm.DB, produkID uint, kuantitas int) error 
+	Create(ctx context.Context, produk *Produk, fotoUrls []string) (*Produk, error)
+	FindAll(ctx context.Context, filter ProdukFilter, page PageRequest) ([]Produk, *PageInfo, error)
+	Facets(ctx context.Context, filter ProdukFilter) (*ProdukFacets, error)
+	FindByID(ctx context.Context, id uint) (*Produk, error)
+	FindBySlug(ctx context.Context, slug string) (*Produk, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]Produk, error)
+	Update(ctx context.Context, produk *Produk) error
+	Delete(ctx context.Context, id uint) error
+	FindFotoByProdukID(ctx context.Context, produkID uint) ([]FotoProduk, error)
+	// UpdateStok adds kuantitas (negative to take stock) and fails with
+	// INSUFFICIENT_STOCK instead of letting stok drop below zero.
+	UpdateStok(ctx context.Context, produkID uint, kuantitas int) error
 }
 
 type ProdukUsecase interface {
-	CreateProduk(req *CreateProdukRequest, userID uint) (*Produk, error)
-	GetAllProduk(filter ProdukFilter, page, limit int) ([]Produk, *PaginationResponse, error)
-	GetProdukByID(id uint) (*Produk, error)
-	UpdateProduk(id uint, req *UpdateProdukRequest, userID uint) (*Produk, error)
-	DeleteProduk(id uint, userID uint) error
+	CreateProduk(ctx context.Context, req *CreateProdukRequest, userID uint) (*Produk, error)
+	GetAllProduk(ctx context.Context, filter ProdukFilter, page PageRequest) (*Page[Produk], *ProdukFacets, error)
+	GetProdukByID(ctx context.Context, id uint) (*Produk, error)
+	UpdateProduk(ctx context.Context, id uint, req *UpdateProdukRequest, userID uint) (*Produk, error)
+	DeleteProduk(ctx context.Context, id uint, userID uint) error
 }
 
 type LogProduk struct {
@@ -75,6 +80,7 @@ type FotoProduk struct {
 
 type CreateProdukRequest struct {
 	NamaProduk    string   `form:"nama_produk" binding:"required"`
+	IdToko        uint     `form:"toko_id"`
 	IdCategory    uint     `form:"category_id" binding:"required"`
 	HargaReseller int      `form:"harga_reseller" binding:"required"`
 	HargaKonsumen int      `form:"harga_konsumen" binding:"required"`
@@ -92,10 +98,92 @@ type UpdateProdukRequest struct {
 	Deskripsi     string `form:"deskripsi"`
 }
 
+// ProdukFilter narrows the product list. A non-empty Query switches to
+// full-text search: results are ordered by relevance and carry a Highlight.
 type ProdukFilter struct {
-	NamaProduk   string
+	Query        string
 	CategoryID   uint
 	TokoID       uint
 	MinHarga     int
 	MaxHarga     int
+	InStock      bool
+	Sort         ProdukSort
+}
+
+type ProdukSort string
+
+const (
+	ProdukSortRelevance   ProdukSort = "relevance"
+	ProdukSortNewest      ProdukSort = "newest"
+	ProdukSortPriceAsc    ProdukSort = "price_asc"
+	ProdukSortPriceDesc   ProdukSort = "price_desc"
+	ProdukSortBestSelling ProdukSort = "best_selling"
+)
+
+// ProdukSorts lists the accepted sort values. Relevance only applies to a
+// search; without one it falls back to newest. A rating sort is left out on
+// purpose: products have no reviews to rate them by yet.
+var ProdukSorts = []ProdukSort{ProdukSortRelevance, ProdukSortNewest, ProdukSortPriceAsc, ProdukSortPriceDesc, ProdukSortBestSelling}
+
+func (s ProdukSort) Valid() bool {
+	for _, sort := range ProdukSorts {
+		if s == sort {
+			return true
+		}
+	}
+	return false
+}
+
+// ProdukListResponse is a product page with the facets of the whole result.
+type ProdukListResponse struct {
+	PaginationResponse
+	Facets *ProdukFacets `json:"facets,omitempty"`
+}
+
+// ProdukPage is the /api/v2 product page, with the facets of the whole result.
+type ProdukPage struct {
+	Page[Produk]
+	Facets *ProdukFacets `json:"facets,omitempty"`
+}
+
+// ProdukFacets are the counts for the filter sidebar. Each dimension is counted
+// with every other filter applied but its own, so picking a category still
+// shows how many products the other categories have.
+type ProdukFacets struct {
+	Categories  []FacetCount      `json:"categories"`
+	Tokos       []FacetCount      `json:"tokos"`
+	PriceRanges []PriceRangeFacet `json:"price_ranges"`
+	InStock     int64             `json:"in_stock"`
+}
+
+type FacetCount struct {
+	ID    uint   `json:"id"`
+	Nama  string `json:"nama"`
+	Count int64  `json:"count"`
+}
+
+// PriceRange bounds are inclusive and map directly onto min_harga/max_harga;
+// Max 0 means no upper bound.
+type PriceRange struct {
+	Min int `json:"min"`
+	Max int `json:"max,omitempty"`
+}
+
+type PriceRangeFacet struct {
+	PriceRange
+	Count int64 `json:"count"`
+}
+
+var ProdukPriceRanges = []PriceRange{
+	{Min: 0, Max: 10000},
+	{Min: 10001, Max: 25000},
+	{Min: 25001, Max: 50000},
+	{Min: 50001, Max: 100000},
+	{Min: 100001},
+}
+
+// ProdukHighlight holds HTML-escaped text with matched words wrapped in <mark>.
+type ProdukHighlight struct {
+	NamaProduk string `json:"nama_produk"`
+	Deskripsi  string `json:"deskripsi,omitempty"`
 }
\ No newline at end of file
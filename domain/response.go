@@ -3,6 +3,7 @@ package domain
 type Response struct {
 	Status  bool        `json:"status"`
 	Message string      `json:"message"`
+	Code    ErrorCode   `json:"code,omitempty"`
 	Errors  interface{} `json:"errors,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
 }
@@ -31,9 +32,59 @@ func SuccessResponse(message string, data interface{}) Response {
 }
 
 type PaginationResponse struct {
-	Page      int         `json:"page"`
-	Limit     int         `json:"limit"`
-	TotalPage int         `json:"total_page,omitempty"`
-	TotalData int         `json:"total_data,omitempty"`
-	Data      interface{} `json:"data,omitempty"`
-}
\ No newline at end of file
+	Page       int         `json:"page,omitempty"`
+	Limit      int         `json:"limit"`
+	TotalPage  int         `json:"total_page,omitempty"`
+	TotalData  int         `json:"total_data,omitempty"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	PrevCursor string      `json:"prev_cursor,omitempty"`
+	Data       interface{} `json:"data,omitempty"`
+}
+
+// PageRequest picks a page by number (OFFSET paging, kept for existing
+// clients) or, when Cursor is set, by an opaque cursor from a previous
+// response. The total is only counted when WithTotal is set.
+type PageRequest struct {
+	Page      int
+	Limit     int
+	Cursor    string
+	WithTotal bool
+}
+
+// PageInfo is returned by repositories next to the rows of a page. Total is
+// nil unless it was requested.
+type PageInfo struct {
+	NextCursor string
+	PrevCursor string
+	Total      *int64
+}
+
+// Page is one page of a listing, the response shape of every list endpoint in
+// /api/v2. TotalData and TotalPage are only set when the total was counted.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	TotalData  *int64 `json:"total_data,omitempty"`
+	TotalPage  *int   `json:"total_page,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// Legacy converts p to the /api/v1 pagination shape.
+func (p *Page[T]) Legacy() *PaginationResponse {
+	pagination := &PaginationResponse{
+		Page:       p.Page,
+		Limit:      p.Limit,
+		NextCursor: p.NextCursor,
+		PrevCursor: p.PrevCursor,
+		Data:       p.Items,
+	}
+	if p.TotalData != nil {
+		pagination.TotalData = int(*p.TotalData)
+	}
+	if p.TotalPage != nil {
+		pagination.TotalPage = *p.TotalPage
+	}
+	return pagination
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type Role string
+
+const (
+	RoleAdmin           Role = "admin"
+	RoleSeller          Role = "seller"
+	RoleSellerStaff     Role = "seller_staff"
+	RoleCustomerService Role = "customer_service"
+	RoleCourier         Role = "courier"
+)
+
+type Permission string
+
+const (
+	PermissionCategoryManage Permission = "category:manage"
+	PermissionUserRead       Permission = "user:read"
+	PermissionUserManage     Permission = "user:manage"
+	PermissionRoleManage     Permission = "role:manage"
+	PermissionProdukManage   Permission = "produk:manage"
+	PermissionOrderRead      Permission = "order:read"
+	PermissionOrderManage    Permission = "order:manage"
+	PermissionOrderDeliver   Permission = "order:deliver"
+)
+
+var RolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermissionCategoryManage,
+		PermissionUserRead,
+		PermissionUserManage,
+		PermissionRoleManage,
+		PermissionProdukManage,
+		PermissionOrderRead,
+		PermissionOrderManage,
+		PermissionOrderDeliver,
+	},
+	RoleSeller: {
+		PermissionProdukManage,
+	},
+	RoleSellerStaff: {
+		PermissionProdukManage,
+	},
+	RoleCustomerService: {
+		PermissionUserRead,
+		PermissionOrderRead,
+	},
+	RoleCourier: {
+		PermissionOrderRead,
+		PermissionOrderDeliver,
+	},
+}
+
+func IsValidRole(role Role) bool {
+	_, ok := RolePermissions[role]
+	return ok
+}
+
+func RoleHasPermission(role Role, permission Permission) bool {
+	for _, p := range RolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+type UserRole struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	IdUser    uint      `gorm:"not null;uniqueIndex:idx_user_role" json:"user_id"`
+	Role      Role      `gorm:"size:50;not null;uniqueIndex:idx_user_role" json:"role"`
+	User      *User     `gorm:"foreignKey:IdUser;references:ID" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UserRoleRepository interface {
+	FindByUserID(ctx context.Context, userID uint) ([]UserRole, error)
+	Create(ctx context.Context, userRole *UserRole) error
+	Delete(ctx context.Context, userID uint, role Role) error
+}
+
+type RoleUsecase interface {
+	GetUserRoles(ctx context.Context, userID uint) ([]Role, error)
+	GrantRole(ctx context.Context, userID uint, role Role) ([]Role, error)
+	RevokeRole(ctx context.Context, userID uint, role Role) ([]Role, error)
+}
+
+type GrantRoleRequest struct {
+	Role Role `json:"role" binding:"required"`
+}
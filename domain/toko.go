@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"time"
 
 	"gorm.io/gorm"
@@ -19,19 +20,19 @@ type Toko struct {
 }
 
 type TokoRepository interface {
-	Create(toko *Toko) error
-	FindByUserID(userID uint) (*Toko, error)
-	FindByID(id uint) (*Toko, error)
-	Update(toko *Toko) error
-	Delete(toko *Toko) error
-	FindAll(filter TokoFilter, offset, limit int) ([]Toko, int64, error)
+	Create(ctx context.Context, toko *Toko) error
+	FindByUserID(ctx context.Context, userID uint) (*Toko, error)
+	FindByID(ctx context.Context, id uint) (*Toko, error)
+	Update(ctx context.Context, toko *Toko) error
+	Delete(ctx context.Context, toko *Toko) error
+	FindAll(ctx context.Context, filter TokoFilter, page PageRequest) ([]Toko, *PageInfo, error)
 }
 
 type TokoUsecase interface { 
-	GetMyToko(userID uint) (*Toko, error)
-	UpdateToko(id uint, req *UpdateTokoRequest, userID uint) (*Toko, error)
-	GetAllTokos(filter TokoFilter, page, limit int) ([]Toko, *PaginationResponse, error)
-	GetTokoByID(id uint) (*Toko, error)
+	GetMyToko(ctx context.Context, userID uint) (*Toko, error)
+	UpdateToko(ctx context.Context, id uint, req *UpdateTokoRequest, userID uint) (*Toko, error)
+	GetAllTokos(ctx context.Context, filter TokoFilter, page PageRequest) (*Page[Toko], error)
+	GetTokoByID(ctx context.Context, id uint) (*Toko, error)
 }
 
 type UpdateTokoRequest struct {
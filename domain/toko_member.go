@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type TokoRole string
+
+const (
+	TokoRoleOwner   TokoRole = "owner"
+	TokoRoleManager TokoRole = "manager"
+	TokoRolePacker  TokoRole = "packer"
+)
+
+type TokoPermission string
+
+const (
+	TokoPermissionTokoWrite     TokoPermission = "toko:write"
+	TokoPermissionMembersManage TokoPermission = "members:manage"
+	TokoPermissionProdukWrite   TokoPermission = "produk:write"
+	TokoPermissionOrdersRead    TokoPermission = "orders:read"
+	TokoPermissionOrdersWrite   TokoPermission = "orders:write"
+	TokoPermissionApiKeysManage TokoPermission = "api_keys:manage"
+)
+
+var TokoRolePermissions = map[TokoRole][]TokoPermission{
+	TokoRoleOwner: {
+		TokoPermissionTokoWrite,
+		TokoPermissionMembersManage,
+		TokoPermissionProdukWrite,
+		TokoPermissionOrdersRead,
+		TokoPermissionOrdersWrite,
+		TokoPermissionApiKeysManage,
+	},
+	TokoRoleManager: {
+		TokoPermissionMembersManage,
+		TokoPermissionProdukWrite,
+		TokoPermissionOrdersRead,
+		TokoPermissionOrdersWrite,
+		TokoPermissionApiKeysManage,
+	},
+	TokoRolePacker: {
+		TokoPermissionOrdersRead,
+		TokoPermissionOrdersWrite,
+	},
+}
+
+func IsValidTokoRole(role TokoRole) bool {
+	_, ok := TokoRolePermissions[role]
+	return ok
+}
+
+func TokoRoleHasPermission(role TokoRole, permission TokoPermission) bool {
+	for _, p := range TokoRolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+type TokoMember struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	IdToko    uint      `gorm:"not null;uniqueIndex:idx_toko_member" json:"toko_id"`
+	IdUser    uint      `gorm:"not null;uniqueIndex:idx_toko_member;index" json:"user_id"`
+	Role      TokoRole  `gorm:"size:50;not null" json:"role"`
+	Toko      *Toko     `gorm:"foreignKey:IdToko;references:ID" json:"toko,omitempty"`
+	User      *User     `gorm:"foreignKey:IdUser;references:ID" json:"user,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (m *TokoMember) Can(permission TokoPermission) bool {
+	return m != nil && TokoRoleHasPermission(m.Role, permission)
+}
+
+const (
+	InvitationStatusPending   = "pending"
+	InvitationStatusAccepted  = "accepted"
+	InvitationStatusDeclined  = "declined"
+	InvitationStatusCancelled = "cancelled"
+)
+
+type TokoInvitation struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	IdToko     uint       `gorm:"not null;index" json:"toko_id"`
+	Email      string     `gorm:"size:255;index" json:"email,omitempty"`
+	NoTelp     string     `gorm:"size:255;index" json:"no_telp,omitempty"`
+	Role       TokoRole   `gorm:"size:50;not null" json:"role"`
+	Status     string     `gorm:"size:50;not null;default:'pending';index" json:"status"`
+	InvitedBy  uint       `gorm:"not null" json:"invited_by"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	Toko       *Toko      `gorm:"foreignKey:IdToko;references:ID" json:"toko,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type TokoMemberRepository interface {
+	Create(ctx context.Context, member *TokoMember) error
+	Update(ctx context.Context, member *TokoMember) error
+	Delete(ctx context.Context, tokoID, userID uint) error
+	FindByTokoAndUser(ctx context.Context, tokoID, userID uint) (*TokoMember, error)
+	FindAllByTokoID(ctx context.Context, tokoID uint) ([]TokoMember, error)
+	FindAllByTokoIDPaged(ctx context.Context, tokoID uint, page PageRequest) ([]TokoMember, *PageInfo, error)
+	FindAllByUserID(ctx context.Context, userID uint) ([]TokoMember, error)
+	FindAllByUserIDPaged(ctx context.Context, userID uint, page PageRequest) ([]TokoMember, *PageInfo, error)
+}
+
+type TokoInvitationRepository interface {
+	Create(ctx context.Context, invitation *TokoInvitation) error
+	Update(ctx context.Context, invitation *TokoInvitation) error
+	// MarkAccepted only accepts an invitation that is still pending and not expired.
+	MarkAccepted(ctx context.Context, id uint, acceptedAt time.Time) error
+	FindByID(ctx context.Context, id uint) (*TokoInvitation, error)
+	FindPendingByTokoID(ctx context.Context, tokoID uint) ([]TokoInvitation, error)
+	FindPendingByTokoIDPaged(ctx context.Context, tokoID uint, page PageRequest) ([]TokoInvitation, *PageInfo, error)
+	FindPendingByContact(ctx context.Context, email, noTelp string) ([]TokoInvitation, error)
+	FindPendingByContactPaged(ctx context.Context, email, noTelp string, page PageRequest) ([]TokoInvitation, *PageInfo, error)
+}
+
+type TokoMemberUsecase interface {
+	GetMyMemberships(ctx context.Context, userID uint) ([]TokoMember, error)
+	ListMyMemberships(ctx context.Context, userID uint, page PageRequest) (*Page[TokoMember], error)
+	GetMembers(ctx context.Context, tokoID, userID uint) ([]TokoMember, error)
+	ListMembers(ctx context.Context, tokoID, userID uint, page PageRequest) (*Page[TokoMember], error)
+	InviteMember(ctx context.Context, tokoID uint, req *InviteTokoMemberRequest, userID uint) (*TokoInvitation, error)
+	GetTokoInvitations(ctx context.Context, tokoID, userID uint) ([]TokoInvitation, error)
+	ListTokoInvitations(ctx context.Context, tokoID, userID uint, page PageRequest) (*Page[TokoInvitation], error)
+	CancelInvitation(ctx context.Context, tokoID, invitationID, userID uint) error
+	GetMyInvitations(ctx context.Context, userID uint) ([]TokoInvitation, error)
+	ListMyInvitations(ctx context.Context, userID uint, page PageRequest) (*Page[TokoInvitation], error)
+	AcceptInvitation(ctx context.Context, invitationID, userID uint) (*TokoMember, error)
+	DeclineInvitation(ctx context.Context, invitationID, userID uint) error
+	UpdateMemberRole(ctx context.Context, tokoID, memberUserID uint, req *UpdateTokoMemberRequest, userID uint) (*TokoMember, error)
+	RemoveMember(ctx context.Context, tokoID, memberUserID, userID uint) error
+}
+
+type InviteTokoMemberRequest struct {
+	Email  string   `json:"email" binding:"omitempty,email"`
+	NoTelp string   `json:"no_telp"`
+	Role   TokoRole `json:"role" binding:"required"`
+}
+
+type UpdateTokoMemberRequest struct {
+	Role TokoRole `json:"role" binding:"required"`
+}
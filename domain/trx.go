@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"time"
 
 	"gorm.io/gorm"
@@ -24,17 +25,49 @@ type Trx struct {
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
+const (
+	TrxStatusPending   = "pending"
+	TrxStatusProcessed = "processed"
+	TrxStatusShipped   = "shipped"
+	TrxStatusCompleted = "completed"
+	TrxStatusCancelled = "cancelled"
+)
+
+var TrxStatusTransitions = map[string][]string{
+	TrxStatusPending:   {TrxStatusProcessed, TrxStatusCancelled},
+	TrxStatusProcessed: {TrxStatusShipped, TrxStatusCancelled},
+	TrxStatusShipped:   {TrxStatusCompleted},
+}
+
+func CanTransitionTrxStatus(from, to string) bool {
+	for _, next := range TrxStatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
 type TrxRepository interface {
-	Create(trx *Trx, details []DetailTrx, logs []LogProduk) (*Trx, error)
-	FindByID(id, userID uint) (*Trx, error)
-	FindAllByUserID(userID uint, filter TrxFilter, limit, offset int) ([]Trx, int64, error) 
-	FindByIDAndUserID(id uint, userID uint) (*Trx, error) 
+	Create(ctx context.Context, trx *Trx, details []DetailTrx, logs []LogProduk) (*Trx, error)
+	FindByID(ctx context.Context, id uint) (*Trx, error)
+	FindAllByUserID(ctx context.Context, userID uint, filter TrxFilter, page PageRequest) ([]Trx, *PageInfo, error) 
+	FindByIDAndUserID(ctx context.Context, id uint, userID uint) (*Trx, error) 
+	FindAll(ctx context.Context, filter TrxFilter, page PageRequest) ([]Trx, *PageInfo, error)
+	FindAllByTokoID(ctx context.Context, tokoID uint, filter TrxFilter, page PageRequest) ([]Trx, *PageInfo, error)
+	FindByIDAndTokoID(ctx context.Context, id uint, tokoID uint) (*Trx, error)
+	UpdateStatus(ctx context.Context, id uint, from, to string) error
 }
 
 type TrxUsecase interface {
-	CreateTransaksi(req *CreateTransaksiRequest, userID uint) (*Trx, error)
-	GetAllTransaksiUser(userID uint, filter TrxFilter, page, limit int) ([]Trx, *PaginationResponse, error) 
-	GetTransaksiByID(id uint, userID uint) (*Trx, error)
+	CreateTransaksi(ctx context.Context, req *CreateTransaksiRequest, userID uint) (*Trx, error)
+	GetAllTransaksiUser(ctx context.Context, userID uint, filter TrxFilter, page PageRequest) (*Page[Trx], error) 
+	GetTransaksiByID(ctx context.Context, id uint, userID uint) (*Trx, error)
+	GetAllTransaksiToko(ctx context.Context, tokoID uint, userID uint, filter TrxFilter, page PageRequest) (*Page[Trx], error)
+	GetTransaksiTokoByID(ctx context.Context, id uint, tokoID uint, userID uint) (*Trx, error)
+	UpdateStatusTransaksiToko(ctx context.Context, id uint, tokoID uint, req *UpdateTrxStatusRequest, userID uint) (*Trx, error)
+	GetAllTransaksi(ctx context.Context, userID uint, filter TrxFilter, page PageRequest) (*Page[Trx], error)
+	UpdateStatusTransaksi(ctx context.Context, id uint, req *UpdateTrxStatusRequest, userID uint) (*Trx, error)
 }
 
 type CreateTransaksiRequest struct {
@@ -43,6 +76,10 @@ type CreateTransaksiRequest struct {
 	DetailTrx     []CreateDetailTrxRequest `json:"detail_trx" binding:"required,min=1,dive"`
 }
 
+type UpdateTrxStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
 type TrxFilter struct {
     KodeInvoice string
     Status      string
@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+// TxManager runs several repository calls as one unit of work. Repositories
+// called with the ctx handed to fn join the transaction, which is committed
+// when fn returns nil and rolled back otherwise. Nested calls use savepoints.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
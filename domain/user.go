@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"time"
 
 	"gorm.io/gorm"
@@ -19,10 +20,13 @@ type User struct {
 	IdProvinsi   string         `gorm:"size:255" json:"id_provinsi"` 
 	IdKota       string         `gorm:"size:255" json:"id_kota"`     
 	IsAdmin      bool           `gorm:"default:false" json:"is_admin"`
+	NoPassword   bool           `gorm:"not null;default:false" json:"-"`
+	SuspendedAt  *time.Time     `gorm:"index" json:"suspended_at,omitempty"`
 
 	Toko        *Toko           `gorm:"foreignKey:IdUser" json:"toko,omitempty"`    
 	Alamat      []Alamat        `gorm:"foreignKey:IdUser" json:"alamat,omitempty"`    
 	Trx         []Trx           `gorm:"foreignKey:IdUser" json:"trx,omitempty"` 
+	Roles       []UserRole      `gorm:"foreignKey:IdUser" json:"roles,omitempty"`
 
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
@@ -30,19 +34,55 @@ type User struct {
 }
 
 type UserRepository interface {
-	Create(user *User) error
-	Update(user *User) error
-	Delete(user *User) error
-	FindAll() ([]User, error)
-	FindById(id uint) (*User, error)
-	FindByEmail(email string) (*User, error)
-	FindByNoTelp(noTelp string) (*User, error)
+	Create(ctx context.Context, user *User) error
+	Update(ctx context.Context, user *User) error
+	Delete(ctx context.Context, user *User) error
+	FindAll(ctx context.Context, filter UserFilter, page PageRequest) ([]User, *PageInfo, error)
+	FindById(ctx context.Context, id uint) (*User, error)
+	FindByEmail(ctx context.Context, email string) (*User, error)
+	FindByNoTelp(ctx context.Context, noTelp string) (*User, error)
 }
 
 type UserUsecase interface {
-	GetProfileById(id uint) (*User, error)
-	UpdateProfile(id uint, req *UpdateProfileRequest) (*User, error)
-	DeleteProfile(id uint) error
+	GetProfileById(ctx context.Context, id uint) (*User, error)
+	// GetRoles loads the current roles of user, the admin flag included.
+	GetRoles(ctx context.Context, user *User) ([]Role, error)
+	UpdateProfile(ctx context.Context, id uint, req *UpdateProfileRequest) (*User, error)
+	DeleteProfile(ctx context.Context, id uint) error
+	ExportData(ctx context.Context, id uint) (*UserDataExport, error)
+	DeleteAccount(ctx context.Context, id uint, req *DeleteAccountRequest) error
+}
+
+type AccountRepository interface {
+	ExportData(ctx context.Context, userID uint) (*UserDataExport, error)
+	Anonymize(ctx context.Context, userID uint, at time.Time) error
+}
+
+type UserDataExport struct {
+	ExportedAt  time.Time      `json:"exported_at"`
+	Profile     *User          `json:"profile"`
+	Alamat      []Alamat       `json:"alamat"`
+	Transaksi   []Trx          `json:"transaksi"`
+	Memberships []TokoMember   `json:"toko_memberships"`
+	Identities  []UserIdentity `json:"identities"`
+}
+
+type DeleteAccountRequest struct {
+	KataSandi string `json:"kata_sandi"`
+}
+
+type UserFilter struct {
+	Query  string
+	Status string
+}
+
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+)
+
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
 }
 
 type UpdateProfileRequest struct {
@@ -1,33 +1,228 @@
 package helper
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"gogroceries/config"
 	"gogroceries/domain"
+	"math/big"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const defaultJWTSecret = "secret"
+
 type JWTInterface interface {
 	GenerateToken(claims *domain.JWTClaims) (string, error)
 	ValidateToken(tokenString string) (*domain.JWTClaims, error)
 	ExtractJWTUser(c *gin.Context) (*domain.JWTClaims, error)
+	JWKS() JWKSet
+	AccessTTL() time.Duration
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+type verificationKey struct {
+	method jwt.SigningMethod
+	key    interface{}
 }
 
 type jwtHelper struct {
-	secretKey []byte
+	method     jwt.SigningMethod
+	signingKey interface{}
+	keyID      string
+	verifyKeys map[string]verificationKey
+	jwks       JWKSet
+	accessTTL  time.Duration
+	leeway     time.Duration
+}
+
+func NewJWTHelper(cfg config.Config) (JWTInterface, error) {
+	j := &jwtHelper{
+		verifyKeys: map[string]verificationKey{},
+		jwks:       JWKSet{Keys: []JWK{}},
+		accessTTL:  cfg.JWTAccessTTL,
+		leeway:     cfg.JWTLeeway,
+	}
+	if j.accessTTL <= 0 {
+		j.accessTTL = 24 * time.Hour
+	}
+
+	switch strings.ToUpper(cfg.JWTAlgorithm) {
+	case "", "HS256":
+		if cfg.JWTSecret == "" || (cfg.JWTSecret == defaultJWTSecret && !cfg.AllowsDefaultSecrets()) {
+			return nil, errors.New("JWT_SECRET must be changed from the default outside development and test")
+		}
+		if cfg.JWTVerifyKeys != "" {
+			return nil, errors.New("JWT_VERIFY_KEYS is only supported with RS256 and EdDSA")
+		}
+		j.method = jwt.SigningMethodHS256
+		j.signingKey = []byte(cfg.JWTSecret)
+		j.keyID = cfg.JWTKeyID
+		j.verifyKeys[j.keyID] = verificationKey{method: j.method, key: j.signingKey}
+		return j, nil
+
+	case "RS256", "EDDSA":
+		if cfg.JWTPrivateKeyFile == "" {
+			return nil, fmt.Errorf("JWT_PRIVATE_KEY_FILE is required for %s", cfg.JWTAlgorithm)
+		}
+		pemBytes, err := os.ReadFile(cfg.JWTPrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT private key: %w", err)
+		}
+
+		var publicKey crypto.PublicKey
+		if strings.ToUpper(cfg.JWTAlgorithm) == "RS256" {
+			privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse RSA private key: %w", err)
+			}
+			j.method = jwt.SigningMethodRS256
+			j.signingKey = privateKey
+			publicKey = &privateKey.PublicKey
+		} else {
+			privateKey, err := jwt.ParseEdPrivateKeyFromPEM(pemBytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse Ed25519 private key: %w", err)
+			}
+			j.method = jwt.SigningMethodEdDSA
+			j.signingKey = privateKey
+			publicKey = privateKey.(ed25519.PrivateKey).Public()
+		}
+
+		j.keyID, err = j.addPublicKey(cfg.JWTKeyID, publicKey)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported JWT_ALGORITHM %q (use HS256, RS256 or EdDSA)", cfg.JWTAlgorithm)
+	}
+
+	for _, entry := range strings.Split(cfg.JWTVerifyKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kid, path := "", entry
+		if i := strings.Index(entry, "="); i >= 0 {
+			kid, path = entry[:i], entry[i+1:]
+		}
+
+		pemBytes, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT verification key %s: %w", path, err)
+		}
+
+		var publicKey crypto.PublicKey
+		if rsaKey, err := jwt.ParseRSAPublicKeyFromPEM(pemBytes); err == nil {
+			publicKey = rsaKey
+		} else if edKey, err := jwt.ParseEdPublicKeyFromPEM(pemBytes); err == nil {
+			publicKey = edKey
+		} else {
+			return nil, fmt.Errorf("JWT verification key %s is neither an RSA nor an Ed25519 public key", path)
+		}
+
+		if _, err := j.addPublicKey(kid, publicKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return j, nil
+}
+
+func (j *jwtHelper) addPublicKey(kid string, publicKey crypto.PublicKey) (string, error) {
+	var jwk JWK
+	var method jwt.SigningMethod
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		method = jwt.SigningMethodRS256
+		jwk = JWK{
+			Kty: "RSA",
+			Alg: method.Alg(),
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+		jwk = JWK{
+			Kty: "OKP",
+			Alg: method.Alg(),
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key),
+		}
+	default:
+		return "", fmt.Errorf("unsupported JWT public key type %T", publicKey)
+	}
+
+	if kid == "" {
+		kid = jwkThumbprint(jwk)
+	}
+	if _, exists := j.verifyKeys[kid]; exists {
+		return "", fmt.Errorf("duplicate JWT key id %q", kid)
+	}
+
+	jwk.Kid = kid
+	jwk.Use = "sig"
+	j.verifyKeys[kid] = verificationKey{method: method, key: publicKey}
+	j.jwks.Keys = append(j.jwks.Keys, jwk)
+
+	return kid, nil
 }
 
-func NewJWTHelper(cfg config.Config) JWTInterface {
-	return &jwtHelper{
-		secretKey: []byte(cfg.JWTSecret),
+// jwkThumbprint derives a stable key id as defined in RFC 7638.
+func jwkThumbprint(jwk JWK) string {
+	var members interface{}
+	if jwk.Kty == "RSA" {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	} else {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
 	}
+
+	raw, _ := json.Marshal(members)
+	sum := sha256.Sum256(raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
 }
 
 func (j *jwtHelper) GenerateToken(claims *domain.JWTClaims) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString(j.secretKey)
+	token := jwt.NewWithClaims(j.method, claims)
+	if j.keyID != "" {
+		token.Header["kid"] = j.keyID
+	}
+	signedToken, err := token.SignedString(j.signingKey)
 	if err != nil {
 		return "", err
 	}
@@ -36,11 +231,20 @@ func (j *jwtHelper) GenerateToken(claims *domain.JWTClaims) (string, error) {
 
 func (j *jwtHelper) ValidateToken(tokenString string) (*domain.JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &domain.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			kid = j.keyID
+		}
+
+		vk, ok := j.verifyKeys[kid]
+		if !ok {
+			return nil, errors.New("Unknown signing key")
+		}
+		if token.Method.Alg() != vk.method.Alg() {
 			return nil, errors.New("Invalid signing method")
 		}
-		return j.secretKey, nil
-	})
+		return vk.key, nil
+	}, jwt.WithLeeway(j.leeway))
 
 	if err != nil {
 		return nil, err
@@ -53,6 +257,14 @@ func (j *jwtHelper) ValidateToken(tokenString string) (*domain.JWTClaims, error)
 	return nil, errors.New("Token is not valid")
 }
 
+func (j *jwtHelper) JWKS() JWKSet {
+	return j.jwks
+}
+
+func (j *jwtHelper) AccessTTL() time.Duration {
+	return j.accessTTL
+}
+
 func (j *jwtHelper) ExtractJWTUser(c *gin.Context) (*domain.JWTClaims, error) {
 	userClaims, exists := c.Get("user_claims")
 	if !exists {
@@ -65,4 +277,4 @@ func (j *jwtHelper) ExtractJWTUser(c *gin.Context) (*domain.JWTClaims, error) {
 	}
 
 	return claims, nil
-}
\ No newline at end of file
+}
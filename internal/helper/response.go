@@ -1,16 +1,34 @@
 package helper
 
 import (
+	"errors"
+	"fmt"
 	"gogroceries/domain"
+	"gogroceries/internal/i18n"
 	"net/http"
+	"reflect"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 )
 
+// Lang returns the response language negotiated from Accept-Language.
+func Lang(c *gin.Context) i18n.Lang {
+	if lang, ok := c.Get("lang"); ok {
+		return lang.(i18n.Lang)
+	}
+	lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+	c.Set("lang", lang)
+	c.Header("Content-Language", string(lang))
+	return lang
+}
+
 func SendSuccess(c *gin.Context, message string, data interface{}) {
 	c.JSON(http.StatusOK, domain.Response{
 		Status:  true,
-		Message: message,
+		Message: i18n.T(Lang(c), message),
 		Data:    data,
 	})
 }
@@ -18,7 +36,8 @@ func SendSuccess(c *gin.Context, message string, data interface{}) {
 func SendError(c *gin.Context, statusCode int, message string, errors interface{}) {
 	c.JSON(statusCode, domain.Response{
 		Status:  false,
-		Message: message,
+		Message: i18n.T(Lang(c), message),
+		Code:    CodeForStatus(statusCode),
 		Errors:  errors,
 	})
 }
@@ -26,11 +45,115 @@ func SendError(c *gin.Context, statusCode int, message string, errors interface{
 func SendPagination(c *gin.Context, message string, page, limit int, data interface{}) {
 	c.JSON(http.StatusOK, domain.Response{
 		Status:  true,
-		Message: message,
+		Message: i18n.T(Lang(c), message),
 		Data: domain.PaginationResponse{
 			Page:  page,
 			Limit: limit,
 			Data:  data,
 		},
 	})
-}
\ No newline at end of file
+}
+
+// SendPage sends one page of a listing with Link headers to its neighbours.
+func SendPage[T any](c *gin.Context, message string, page *domain.Page[T]) {
+	SetPageLinks(c, page)
+	SendSuccess(c, message, page)
+}
+
+// SetPageLinks sets an RFC 8288 Link header with the first, next and prev
+// pages of the current request, keeping its other query parameters.
+func SetPageLinks[T any](c *gin.Context, page *domain.Page[T]) {
+	links := []string{pageLink(c, "first", "")}
+	if page.NextCursor != "" {
+		links = append(links, pageLink(c, "next", page.NextCursor))
+	}
+	if page.PrevCursor != "" {
+		links = append(links, pageLink(c, "prev", page.PrevCursor))
+	}
+	c.Header("Link", strings.Join(links, ", "))
+}
+
+func pageLink(c *gin.Context, rel, cursor string) string {
+	u := *c.Request.URL
+	query := u.Query()
+	query.Del("page")
+	query.Del("cursor")
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	u.RawQuery = query.Encode()
+	return fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), rel)
+}
+
+func StatusForCode(code domain.ErrorCode) int {
+	switch code {
+	case domain.CodeNotFound:
+		return http.StatusNotFound
+	case domain.CodeForbidden:
+		return http.StatusForbidden
+	case domain.CodeConflict, domain.CodeInsufficientStock:
+		return http.StatusConflict
+	case domain.CodeValidation:
+		return http.StatusBadRequest
+	case domain.CodeUnauthorized:
+		return http.StatusUnauthorized
+	case domain.CodeUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func CodeForStatus(status int) domain.ErrorCode {
+	switch status {
+	case http.StatusNotFound:
+		return domain.CodeNotFound
+	case http.StatusForbidden:
+		return domain.CodeForbidden
+	case http.StatusConflict:
+		return domain.CodeConflict
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusRequestEntityTooLarge:
+		return domain.CodeValidation
+	case http.StatusUnauthorized:
+		return domain.CodeUnauthorized
+	case http.StatusServiceUnavailable:
+		return domain.CodeUnavailable
+	default:
+		return domain.CodeInternal
+	}
+}
+
+// BindingError wraps a gin binding failure as a validation error. Field
+// messages are translated when the error is rendered.
+func BindingError(err error) error {
+	return &domain.Error{Code: domain.CodeValidation, Message: "error.invalid_input", Err: err}
+}
+
+// ValidationFields returns translated per-field messages when err came from
+// the validator.
+func ValidationFields(c *gin.Context, err error) map[string]string {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+	return i18n.ValidationFields(Lang(c), validationErrs)
+}
+
+// SetupValidator makes validation errors report fields by their json/form
+// name and registers the validator translations.
+func SetupValidator() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected validator engine")
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+			if name != "" && name != "-" {
+				return name
+			}
+		}
+		return field.Name
+	})
+	return i18n.RegisterValidator(v)
+}
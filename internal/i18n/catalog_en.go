@@ -0,0 +1,183 @@
+package i18n
+
+var catalogEN = map[string]string{
+	"admin.cannot_delete_self":  "You cannot delete your own account",
+	"admin.cannot_suspend_self": "You cannot suspend your own account",
+	"admin.role_granted":        "Role granted successfully",
+	"admin.role_revoked":        "Role revoked successfully",
+	"admin.user_deleted":        "User deleted successfully",
+	"admin.user_detail":         "User retrieved successfully",
+	"admin.user_list":           "Users retrieved successfully",
+	"admin.user_reactivated":    "User reactivated successfully",
+	"admin.user_roles":          "User roles retrieved successfully",
+	"admin.user_suspended":      "User suspended successfully",
+
+	"alamat.created":    "Address created successfully",
+	"alamat.deleted":    "Address deleted successfully",
+	"alamat.detail":     "Address retrieved successfully",
+	"alamat.invalid_id": "Invalid address ID",
+	"alamat.list":       "Addresses retrieved successfully",
+	"alamat.not_found":  "Address not found",
+	"alamat.updated":    "Address updated successfully",
+
+	"api_key.created":         "API key created successfully, store the key now because it will not be shown again",
+	"api_key.forbidden":       "You cannot manage the API keys of this store",
+	"api_key.inactive":        "API key has been revoked or has expired",
+	"api_key.invalid_id":      "Invalid API key ID",
+	"api_key.list":            "API keys retrieved successfully",
+	"api_key.not_found":       "API key not found",
+	"api_key.revoked":         "API key revoked successfully",
+	"api_key.scope_forbidden": "You do not have the '{scope}' permission",
+	"api_key.scope_invalid":   "Scope '{scope}' is not valid",
+	"api_key.toko_required":   "You manage the API keys of more than one store, toko_id is required",
+
+	"auth.admin_only":            "Access denied: admin only",
+	"auth.api_key_invalid":       "API key is not valid",
+	"auth.api_key_missing_scope": "Access denied: API key is missing a required scope",
+	"auth.api_key_owner_missing": "API key owner no longer exists",
+	"auth.api_key_wrong_toko":    "API key is not valid for this store",
+	"auth.claims_missing":        "User claims not found in context",
+	"auth.header_invalid":        "Wrong Authorization header format (must be: Bearer <token>)",
+	"auth.header_missing":        "Authorization header is required",
+	"auth.invalid_credentials":   "Phone number or password is incorrect",
+	"auth.login_failed":          "Login failed",
+	"auth.login_success":         "Login success",
+	"auth.permission_missing":    "Access denied: missing permission",
+	"auth.register_success":      "Registration success",
+	"auth.suspended":             "Account is suspended",
+	"auth.token_invalid":         "Token is not valid or expired",
+	"auth.user_id_invalid":       "Invalid user ID in context",
+	"auth.user_id_missing":       "User ID not found in context",
+	"auth.user_missing":          "User no longer exists",
+
+	"category.created":           "Category created successfully",
+	"category.deleted":           "Category deleted successfully",
+	"category.detach_conflict":   "Send either parent_id or detach_parent, not both",
+	"category.detail":            "Category retrieved successfully",
+	"category.icon_url_format":   "Must be an http or https URL, or empty to remove the icon",
+	"category.icon_url_invalid":  "Icon URL is not valid",
+	"category.id_not_found":      "Category with ID {id} not found",
+	"category.in_use":            "Category is still in use, merge it into another category first",
+	"category.in_use_children":   "{children} sub-categories are still under this category",
+	"category.in_use_produk":     "{produk} products still use this category",
+	"category.invalid_id":        "Invalid category ID",
+	"category.list":              "Categories retrieved successfully",
+	"category.merged":            "Category merged successfully",
+	"category.not_found":         "Category not found",
+	"category.parent_in_subtree": "A category cannot be a sub-category of itself or of its descendants",
+	"category.parent_invalid":    "Parent category is not valid",
+	"category.parent_not_found":  "Parent category not found",
+	"category.slug_empty":        "A slug cannot be generated from the category name",
+	"category.slug_format":       "Only lowercase letters, digits and hyphens, for example: sayur-daun",
+	"category.slug_invalid":      "Slug is not valid",
+	"category.slug_manual":       "Fill in the slug manually",
+	"category.slug_taken":        "Slug '{slug}' is already used by another category",
+	"category.target_in_subtree": "The target cannot be this category or one of its sub-categories",
+	"category.target_invalid":    "Target category is not valid",
+	"category.target_not_found":  "Target category not found",
+	"category.updated":           "Category updated successfully",
+	"category.usage":             "Category usage retrieved successfully",
+
+	"error.internal":      "Internal server error",
+	"error.invalid_input": "Input is not valid",
+	"error.timeout":       "The request took too long, please try again",
+
+	"health.not_ready": "The service is not ready",
+	"health.ok":        "The service is alive",
+	"health.ready":     "The service is ready to accept traffic",
+
+	"oidc.email_unverified":   "Email from the provider is not verified",
+	"oidc.login_failed":       "Social login failed",
+	"oidc.provider_not_found": "OIDC provider '{provider}' not found",
+	"oidc.state_invalid":      "OAuth state is invalid or expired",
+
+	"pagination.cursor_hint":    "Use next_cursor or prev_cursor from the previous response with the same filters and sort",
+	"pagination.cursor_invalid": "Cursor is not valid",
+
+	"produk.create_forbidden":   "You cannot add products to this store",
+	"produk.created":            "Product created successfully",
+	"produk.delete_forbidden":   "You cannot delete this product",
+	"produk.deleted":            "Product deleted successfully",
+	"produk.detail":             "Product retrieved successfully",
+	"produk.invalid_id":         "Invalid product ID",
+	"produk.list":               "Products retrieved successfully",
+	"produk.no_toko":            "No store found for this user, cannot add products",
+	"produk.not_found":          "Product not found",
+	"produk.photo_required":     "At least 1 product photo is required",
+	"produk.sort_invalid":       "Sort '{sort}' is not valid",
+	"produk.sort_options":       "Must be one of {options}",
+	"produk.stock_insufficient": "Not enough stock for product ID {id}",
+	"produk.toko_id_required":   "You are a member of more than one store, toko_id is required",
+	"produk.update_forbidden":   "You cannot change this product",
+	"produk.updated":            "Product updated successfully",
+
+	"role.invalid": "Role '{role}' is not known",
+
+	"toko.detail":             "Store retrieved successfully",
+	"toko.invalid_id":         "Invalid store ID",
+	"toko.list":               "Stores retrieved successfully",
+	"toko.mine":               "Store retrieved successfully",
+	"toko.not_found":          "Store not found",
+	"toko.not_found_for_user": "No store found for this user",
+	"toko.update_forbidden":   "You cannot change this store",
+	"toko.updated":            "Store updated successfully",
+
+	"toko_member.already_member":        "You are already a member of this store",
+	"toko_member.contact_required":      "email or no_telp is required",
+	"toko_member.invalid_invitation_id": "Invalid invitation ID",
+	"toko_member.invitation_accepted":   "Invitation accepted successfully",
+	"toko_member.invitation_cancelled":  "Invitation cancelled successfully",
+	"toko_member.invitation_declined":   "Invitation declined successfully",
+	"toko_member.invitation_expired":    "The invitation is no longer valid",
+	"toko_member.invitation_not_found":  "Invitation not found",
+	"toko_member.invitation_sent":       "Invitation sent successfully",
+	"toko_member.invite_forbidden":      "You cannot invite members with this role",
+	"toko_member.list":                  "Store members retrieved successfully",
+	"toko_member.manage_forbidden":      "You cannot manage the members of this store",
+	"toko_member.my_invitations":        "Invitations retrieved successfully",
+	"toko_member.my_memberships":        "Store memberships retrieved successfully",
+	"toko_member.not_found":             "Store member not found",
+	"toko_member.not_member":            "You are not a member of this store",
+	"toko_member.owner_not_removable":   "The owner cannot be removed from the store",
+	"toko_member.remove_forbidden":      "You cannot remove this member",
+	"toko_member.removed":               "Member removed successfully",
+	"toko_member.role_forbidden":        "You cannot change the role of this member",
+	"toko_member.role_invalid":          "Store role '{role}' is not valid",
+	"toko_member.role_updated":          "Member role updated successfully",
+	"toko_member.toko_invitations":      "Store invitations retrieved successfully",
+
+	"trx.alamat_invalid":     "Shipping address is not valid or does not belong to you",
+	"trx.all_list":           "Orders retrieved successfully",
+	"trx.created":            "Transaction created successfully",
+	"trx.detail":             "Transaction retrieved successfully",
+	"trx.insufficient_stock": "Not enough stock for '{produk}' (available: {stok}, requested: {kuantitas})",
+	"trx.invalid_id":         "Invalid transaction ID",
+	"trx.list":               "Transactions retrieved successfully",
+	"trx.list_forbidden":     "You have no access to all orders",
+	"trx.not_found":          "Transaction not found",
+	"trx.produk_not_found":   "Product with ID {id} not found",
+	"trx.status_changed":     "The order status has changed, reload the order",
+	"trx.status_transition":  "Order status cannot change from '{from}' to '{to}'",
+	"trx.status_updated":     "Order status updated successfully",
+	"trx.toko_detail":        "Store order retrieved successfully",
+	"trx.toko_forbidden":     "You have no access to this store's orders",
+	"trx.toko_list":          "Store orders retrieved successfully",
+	"trx.update_forbidden":   "You cannot change the status of this order",
+
+	"upload.invalid_form":       "Invalid form data",
+	"upload.save_failed":        "Failed to save uploaded file",
+	"upload.too_large":          "Request body is too large",
+	"upload.unsupported_format": "Unsupported file format",
+
+	"user.account_deleted":       "Account deleted successfully",
+	"user.email_taken":           "Email is already registered",
+	"user.export_failed":         "Export failed",
+	"user.export_invalid_format": "Invalid format, use json or zip",
+	"user.exported":              "User data exported successfully",
+	"user.invalid_id":            "Invalid user ID",
+	"user.not_found":             "User not found",
+	"user.phone_taken":           "Phone number is already registered",
+	"user.profile":               "User profile retrieved successfully",
+	"user.profile_updated":       "Profile updated successfully",
+	"user.wrong_password":        "Wrong password",
+}
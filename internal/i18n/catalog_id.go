@@ -0,0 +1,183 @@
+package i18n
+
+var catalogID = map[string]string{
+	"admin.cannot_delete_self":  "Tidak bisa menghapus akun sendiri",
+	"admin.cannot_suspend_self": "Tidak bisa men-suspend akun sendiri",
+	"admin.role_granted":        "Role berhasil diberikan",
+	"admin.role_revoked":        "Role berhasil dicabut",
+	"admin.user_deleted":        "User berhasil dihapus",
+	"admin.user_detail":         "Berhasil mengambil detail user",
+	"admin.user_list":           "Berhasil mengambil daftar user",
+	"admin.user_reactivated":    "User berhasil diaktifkan kembali",
+	"admin.user_roles":          "Berhasil mengambil role user",
+	"admin.user_suspended":      "User berhasil ditangguhkan",
+
+	"alamat.created":    "Alamat berhasil dibuat",
+	"alamat.deleted":    "Alamat berhasil dihapus",
+	"alamat.detail":     "Berhasil mengambil detail alamat",
+	"alamat.invalid_id": "ID alamat tidak valid",
+	"alamat.list":       "Berhasil mengambil daftar alamat",
+	"alamat.not_found":  "Alamat tidak ditemukan",
+	"alamat.updated":    "Alamat berhasil diupdate",
+
+	"api_key.created":         "API key berhasil dibuat, simpan key sekarang karena tidak akan ditampilkan lagi",
+	"api_key.forbidden":       "Anda tidak bisa mengelola API key toko ini",
+	"api_key.inactive":        "API key sudah dicabut atau kedaluwarsa",
+	"api_key.invalid_id":      "ID API key tidak valid",
+	"api_key.list":            "Berhasil mengambil daftar API key",
+	"api_key.not_found":       "API key tidak ditemukan",
+	"api_key.revoked":         "API key berhasil dicabut",
+	"api_key.scope_forbidden": "Anda tidak memiliki izin '{scope}'",
+	"api_key.scope_invalid":   "Scope '{scope}' tidak valid",
+	"api_key.toko_required":   "Anda mengelola API key lebih dari satu toko, toko_id wajib diisi",
+
+	"auth.admin_only":            "Akses ditolak: khusus admin",
+	"auth.api_key_invalid":       "API key tidak valid",
+	"auth.api_key_missing_scope": "Akses ditolak: API key tidak memiliki scope yang dibutuhkan",
+	"auth.api_key_owner_missing": "Pemilik API key sudah tidak ada",
+	"auth.api_key_wrong_toko":    "API key tidak berlaku untuk toko ini",
+	"auth.claims_missing":        "User claims tidak ditemukan di context",
+	"auth.header_invalid":        "Format header Authorization salah (harus: Bearer <token>)",
+	"auth.header_missing":        "Header Authorization wajib diisi",
+	"auth.invalid_credentials":   "Nomor telepon atau kata sandi salah",
+	"auth.login_failed":          "Login gagal",
+	"auth.login_success":         "Login berhasil",
+	"auth.permission_missing":    "Akses ditolak: tidak memiliki izin",
+	"auth.register_success":      "Registrasi berhasil",
+	"auth.suspended":             "Akun sedang ditangguhkan",
+	"auth.token_invalid":         "Token tidak valid atau sudah kedaluwarsa",
+	"auth.user_id_invalid":       "User ID di context bukan uint",
+	"auth.user_id_missing":       "User ID tidak ditemukan di context",
+	"auth.user_missing":          "User sudah tidak ada",
+
+	"category.created":           "Kategori berhasil dibuat",
+	"category.deleted":           "Kategori berhasil dihapus",
+	"category.detach_conflict":   "Isi parent_id atau detach_parent, jangan keduanya",
+	"category.detail":            "Berhasil mengambil detail kategori",
+	"category.icon_url_format":   "Harus URL http atau https, atau kosong untuk menghapus ikon",
+	"category.icon_url_invalid":  "URL ikon tidak valid",
+	"category.id_not_found":      "Category dengan ID {id} tidak ditemukan",
+	"category.in_use":            "Category masih dipakai, gabungkan dulu ke category lain",
+	"category.in_use_children":   "{children} sub-kategori masih di bawah category ini",
+	"category.in_use_produk":     "{produk} produk masih memakai category ini",
+	"category.invalid_id":        "ID kategori tidak valid",
+	"category.list":              "Berhasil mengambil daftar kategori",
+	"category.merged":            "Kategori berhasil digabungkan",
+	"category.not_found":         "Category tidak ditemukan",
+	"category.parent_in_subtree": "Category tidak bisa menjadi sub-kategori dari dirinya sendiri atau turunannya",
+	"category.parent_invalid":    "Parent category tidak valid",
+	"category.parent_not_found":  "Parent category tidak ditemukan",
+	"category.slug_empty":        "Slug tidak bisa dibuat dari nama category",
+	"category.slug_format":       "Hanya huruf kecil, angka dan tanda hubung, contoh: sayur-daun",
+	"category.slug_invalid":      "Slug tidak valid",
+	"category.slug_manual":       "Isi slug secara manual",
+	"category.slug_taken":        "Slug '{slug}' sudah dipakai category lain",
+	"category.target_in_subtree": "Category tujuan tidak boleh category ini sendiri atau sub-kategorinya",
+	"category.target_invalid":    "Category tujuan tidak valid",
+	"category.target_not_found":  "Category tujuan tidak ditemukan",
+	"category.updated":           "Kategori berhasil diupdate",
+	"category.usage":             "Berhasil mengambil penggunaan kategori",
+
+	"error.internal":      "Terjadi kesalahan pada server",
+	"error.invalid_input": "Input tidak valid",
+	"error.timeout":       "Permintaan terlalu lama diproses, silakan coba lagi",
+
+	"health.not_ready": "Service belum siap",
+	"health.ok":        "Service berjalan",
+	"health.ready":     "Service siap menerima request",
+
+	"oidc.email_unverified":   "Email dari provider belum terverifikasi",
+	"oidc.login_failed":       "Login sosial gagal",
+	"oidc.provider_not_found": "OIDC provider '{provider}' tidak ditemukan",
+	"oidc.state_invalid":      "OAuth state tidak valid atau kedaluwarsa",
+
+	"pagination.cursor_hint":    "Gunakan next_cursor atau prev_cursor dari response sebelumnya dengan filter dan sort yang sama",
+	"pagination.cursor_invalid": "Cursor tidak valid",
+
+	"produk.create_forbidden":   "Anda tidak bisa menambah produk di toko ini",
+	"produk.created":            "Produk berhasil dibuat",
+	"produk.delete_forbidden":   "Anda tidak bisa menghapus produk ini",
+	"produk.deleted":            "Produk berhasil dihapus",
+	"produk.detail":             "Berhasil mengambil detail produk",
+	"produk.invalid_id":         "ID produk tidak valid",
+	"produk.list":               "Berhasil mengambil daftar produk",
+	"produk.no_toko":            "Toko tidak ditemukan untuk user ini, tidak bisa menambah produk",
+	"produk.not_found":          "Produk tidak ditemukan",
+	"produk.photo_required":     "Minimal 1 foto produk dibutuhkan",
+	"produk.sort_invalid":       "Sort '{sort}' tidak valid",
+	"produk.sort_options":       "Harus salah satu dari {options}",
+	"produk.stock_insufficient": "Stok produk ID {id} tidak mencukupi",
+	"produk.toko_id_required":   "Anda anggota lebih dari satu toko, toko_id wajib diisi",
+	"produk.update_forbidden":   "Anda tidak bisa mengubah produk ini",
+	"produk.updated":            "Produk berhasil diupdate",
+
+	"role.invalid": "Role '{role}' tidak dikenal",
+
+	"toko.detail":             "Berhasil mengambil detail toko",
+	"toko.invalid_id":         "ID toko tidak valid",
+	"toko.list":               "Berhasil mengambil daftar toko",
+	"toko.mine":               "Berhasil mengambil toko anda",
+	"toko.not_found":          "Toko tidak ditemukan",
+	"toko.not_found_for_user": "Toko tidak ditemukan untuk user ini",
+	"toko.update_forbidden":   "Anda tidak bisa mengubah toko ini",
+	"toko.updated":            "Toko berhasil diupdate",
+
+	"toko_member.already_member":        "Anda sudah menjadi anggota toko ini",
+	"toko_member.contact_required":      "email atau no_telp wajib diisi",
+	"toko_member.invalid_invitation_id": "ID undangan tidak valid",
+	"toko_member.invitation_accepted":   "Undangan berhasil diterima",
+	"toko_member.invitation_cancelled":  "Undangan berhasil dibatalkan",
+	"toko_member.invitation_declined":   "Undangan berhasil ditolak",
+	"toko_member.invitation_expired":    "Undangan sudah tidak berlaku",
+	"toko_member.invitation_not_found":  "Undangan tidak ditemukan",
+	"toko_member.invitation_sent":       "Undangan berhasil dikirim",
+	"toko_member.invite_forbidden":      "Anda tidak bisa mengundang anggota dengan role ini",
+	"toko_member.list":                  "Berhasil mengambil daftar anggota toko",
+	"toko_member.manage_forbidden":      "Anda tidak bisa mengelola anggota toko ini",
+	"toko_member.my_invitations":        "Berhasil mengambil daftar undangan",
+	"toko_member.my_memberships":        "Berhasil mengambil daftar keanggotaan toko",
+	"toko_member.not_found":             "Anggota toko tidak ditemukan",
+	"toko_member.not_member":            "Anda bukan anggota toko ini",
+	"toko_member.owner_not_removable":   "Owner tidak bisa dikeluarkan dari toko",
+	"toko_member.remove_forbidden":      "Anda tidak bisa mengeluarkan anggota ini",
+	"toko_member.removed":               "Anggota berhasil dikeluarkan",
+	"toko_member.role_forbidden":        "Anda tidak bisa mengubah role anggota ini",
+	"toko_member.role_invalid":          "Role toko '{role}' tidak valid",
+	"toko_member.role_updated":          "Role anggota berhasil diupdate",
+	"toko_member.toko_invitations":      "Berhasil mengambil daftar undangan toko",
+
+	"trx.alamat_invalid":     "Alamat pengiriman tidak valid atau bukan milik anda",
+	"trx.all_list":           "Berhasil mengambil daftar semua pesanan",
+	"trx.created":            "Transaksi berhasil dibuat",
+	"trx.detail":             "Berhasil mengambil detail transaksi",
+	"trx.insufficient_stock": "Stok produk '{produk}' tidak mencukupi (tersedia: {stok}, diminta: {kuantitas})",
+	"trx.invalid_id":         "ID transaksi tidak valid",
+	"trx.list":               "Berhasil mengambil daftar transaksi",
+	"trx.list_forbidden":     "Anda tidak punya akses ke semua pesanan",
+	"trx.not_found":          "Transaksi tidak ditemukan",
+	"trx.produk_not_found":   "Produk dengan ID {id} tidak ditemukan",
+	"trx.status_changed":     "Status transaksi sudah berubah, muat ulang pesanan",
+	"trx.status_transition":  "Status transaksi tidak bisa diubah dari '{from}' ke '{to}'",
+	"trx.status_updated":     "Status pesanan berhasil diupdate",
+	"trx.toko_detail":        "Berhasil mengambil detail pesanan toko",
+	"trx.toko_forbidden":     "Anda tidak punya akses ke pesanan toko ini",
+	"trx.toko_list":          "Berhasil mengambil daftar pesanan toko",
+	"trx.update_forbidden":   "Anda tidak bisa mengubah status pesanan ini",
+
+	"upload.invalid_form":       "Gagal parse form data",
+	"upload.save_failed":        "Gagal menyimpan file upload",
+	"upload.too_large":          "Ukuran request terlalu besar",
+	"upload.unsupported_format": "Format file tidak didukung",
+
+	"user.account_deleted":       "Akun berhasil dihapus",
+	"user.email_taken":           "Email sudah terdaftar",
+	"user.export_failed":         "Gagal mengekspor data",
+	"user.export_invalid_format": "Format tidak valid, gunakan json atau zip",
+	"user.exported":              "Berhasil mengekspor data user",
+	"user.invalid_id":            "ID user tidak valid",
+	"user.not_found":             "User tidak ditemukan",
+	"user.phone_taken":           "Nomor telepon sudah terdaftar",
+	"user.profile":               "Berhasil mengambil profil user",
+	"user.profile_updated":       "Profil berhasil diupdate",
+	"user.wrong_password":        "Kata sandi salah",
+}
@@ -0,0 +1,143 @@
+// Package i18n holds the API message catalogs and validator translations.
+// Messages are looked up by key; the English catalog is the reference and
+// every other catalog must define exactly the same keys.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	id_translations "github.com/go-playground/validator/v10/translations/id"
+)
+
+type Lang string
+
+const (
+	ID Lang = "id"
+	EN Lang = "en"
+
+	Default = ID
+)
+
+var catalogs = map[Lang]map[string]string{
+	ID: catalogID,
+	EN: catalogEN,
+}
+
+var universal = ut.New(en.New(), en.New(), id.New())
+
+// T returns the message for key in lang, falling back to the default
+// language and finally to the key itself so unknown messages pass through.
+func T(lang Lang, key string) string {
+	if msg, ok := catalogs[lang][key]; ok {
+		return msg
+	}
+	if msg, ok := catalogs[Default][key]; ok {
+		return msg
+	}
+	return key
+}
+
+// Format is T with every {name} placeholder replaced by the matching param.
+func Format(lang Lang, key string, params map[string]interface{}) string {
+	msg := T(lang, key)
+	if len(params) == 0 {
+		return msg
+	}
+	pairs := make([]string, 0, 2*len(params))
+	for name, value := range params {
+		pairs = append(pairs, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(pairs...).Replace(msg)
+}
+
+// Negotiate picks the supported language with the highest q-value from an
+// Accept-Language header.
+func Negotiate(header string) Lang {
+	best, bestQ := Default, -1.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		primary, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		lang := Lang(primary)
+		if _, ok := catalogs[lang]; ok && q > bestQ {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
+
+// MissingKeys reports, per language, the keys that differ from the English catalog.
+func MissingKeys() map[Lang][]string {
+	missing := map[Lang][]string{}
+	for lang, catalog := range catalogs {
+		if lang == EN {
+			continue
+		}
+		for key := range catalogEN {
+			if _, ok := catalog[key]; !ok {
+				missing[lang] = append(missing[lang], key)
+			}
+		}
+		for key := range catalog {
+			if _, ok := catalogEN[key]; !ok {
+				missing[EN] = append(missing[EN], key)
+			}
+		}
+	}
+	for lang := range missing {
+		sort.Strings(missing[lang])
+	}
+	return missing
+}
+
+// Check fails when the catalogs are out of sync; it runs at startup so a
+// forgotten translation stops the build pipeline instead of reaching users.
+func Check() error {
+	missing := MissingKeys()
+	if len(missing) == 0 {
+		return nil
+	}
+	var parts []string
+	for lang, keys := range missing {
+		parts = append(parts, fmt.Sprintf("%s: %s", lang, strings.Join(keys, ", ")))
+	}
+	sort.Strings(parts)
+	return fmt.Errorf("i18n catalog keys missing: %s", strings.Join(parts, "; "))
+}
+
+// RegisterValidator installs the validator's built-in translations for every
+// supported language.
+func RegisterValidator(v *validator.Validate) error {
+	enTrans, _ := universal.GetTranslator(string(EN))
+	if err := en_translations.RegisterDefaultTranslations(v, enTrans); err != nil {
+		return err
+	}
+	idTrans, _ := universal.GetTranslator(string(ID))
+	return id_translations.RegisterDefaultTranslations(v, idTrans)
+}
+
+// ValidationFields translates validator errors into a field -> message map.
+func ValidationFields(lang Lang, errs validator.ValidationErrors) map[string]string {
+	trans, _ := universal.GetTranslator(string(lang))
+	fields := make(map[string]string, len(errs))
+	for _, fe := range errs {
+		fields[fe.Field()] = fe.Translate(trans)
+	}
+	return fields
+}
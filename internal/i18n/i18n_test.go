@@ -0,0 +1,156 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	for lang, keys := range MissingKeys() {
+		t.Errorf("%s catalog is missing %s", lang, strings.Join(keys, ", "))
+	}
+}
+
+var placeholder = regexp.MustCompile(`\{[a-z_]+\}`)
+
+func TestCatalogsHaveSamePlaceholders(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for key, msg := range catalog {
+			want := placeholders(catalogEN[key])
+			if got := placeholders(msg); got != want {
+				t.Errorf("%s %q uses %s, en uses %s", lang, key, got, want)
+			}
+		}
+	}
+}
+
+func placeholders(msg string) string {
+	found := placeholder.FindAllString(msg, -1)
+	sort.Strings(found)
+	return strings.Join(found, " ")
+}
+
+// messageArg is the position of the message key in the response helpers.
+var messageArg = map[string]int{
+	"SendSuccess":    1,
+	"SendError":      2,
+	"SendPagination": 1,
+	"SendPage":       1,
+}
+
+// TestUsedKeysExist parses the code that produces API messages and checks
+// that every literal key handed to the response helpers, the domain error
+// constructors and domain.Error fields is in the catalog.
+func TestUsedKeysExist(t *testing.T) {
+	used := map[string]string{}
+	for _, dir := range []string{"delivery", "usecase", "repository", "internal/helper"} {
+		err := filepath.WalkDir(filepath.Join("..", "..", dir), func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return err
+			}
+			return collectKeys(path, used)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(used) < 100 {
+		t.Fatalf("found only %d message keys, the scanner is probably broken", len(used))
+	}
+	for key, pos := range used {
+		if _, ok := catalogEN[key]; !ok {
+			t.Errorf("%s: key %q is not in the catalog", pos, key)
+		}
+	}
+}
+
+func collectKeys(path string, used map[string]string) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	add := func(expr ast.Expr) {
+		lit, ok := expr.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return
+		}
+		if key, err := strconv.Unquote(lit.Value); err == nil {
+			used[key] = fset.Position(lit.Pos()).String()
+		}
+	}
+	addFields := func(expr ast.Expr) {
+		if fields, ok := expr.(*ast.CompositeLit); ok {
+			for _, elt := range fields.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					add(kv.Value)
+				}
+			}
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			name := calleeName(n.Fun)
+			if i, ok := messageArg[name]; ok && len(n.Args) > i {
+				add(n.Args[i])
+			}
+			if strings.HasPrefix(name, "New") && strings.HasSuffix(name, "Error") && len(n.Args) > 0 {
+				add(n.Args[0])
+				if name == "NewValidationError" && len(n.Args) > 1 {
+					addFields(n.Args[1])
+				}
+			}
+		case *ast.CompositeLit:
+			if calleeName(n.Type) != "Error" {
+				return true
+			}
+			for _, elt := range n.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				switch calleeName(kv.Key) {
+				case "Message":
+					add(kv.Value)
+				case "Fields":
+					addFields(kv.Value)
+				}
+			}
+		case *ast.AssignStmt:
+			// fields["produk"] = "category.in_use_produk"
+			for i, lhs := range n.Lhs {
+				if index, ok := lhs.(*ast.IndexExpr); ok && calleeName(index.X) == "fields" && i < len(n.Rhs) {
+					add(n.Rhs[i])
+				}
+			}
+		}
+		return true
+	})
+	return nil
+}
+
+func calleeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.IndexExpr:
+		return calleeName(e.X)
+	case *ast.IndexListExpr:
+		return calleeName(e.X)
+	}
+	return ""
+}
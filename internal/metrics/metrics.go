@@ -0,0 +1,97 @@
+// Package metrics defines the Prometheus collectors exposed on /metrics.
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "gogroceries"
+
+// Checkout failure reasons.
+const (
+	ReasonInvalidAddress    = "invalid_address"
+	ReasonProductNotFound   = "product_not_found"
+	ReasonInsufficientStock = "insufficient_stock"
+	ReasonInternal          = "internal"
+)
+
+// Registry holds every collector of the service, including the Go runtime and
+// process collectors.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by Gin route, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+
+	OrdersCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_created_total",
+		Help:      "Transactions created at checkout.",
+	})
+
+	OrderItems = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "order_items_total",
+		Help:      "Units of product sold at checkout.",
+	})
+
+	Revenue = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "revenue_rupiah_total",
+		Help:      "Total price of the transactions created at checkout, in rupiah.",
+	})
+
+	CheckoutFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "checkout_failures_total",
+		Help:      "Checkouts that did not create a transaction, by reason.",
+	}, []string{"reason"})
+
+	OrderStatusChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "order_status_changes_total",
+		Help:      "Transaction status updates by the seller, by new status.",
+	}, []string{"status"})
+
+	ProductChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "product_changes_total",
+		Help:      "Products created, updated and deleted.",
+	}, []string{"action"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		HTTPRequestsInFlight,
+		OrdersCreated,
+		OrderItems,
+		Revenue,
+		CheckoutFailures,
+		OrderStatusChanges,
+		ProductChanges,
+	)
+	for _, reason := range []string{ReasonInvalidAddress, ReasonProductNotFound, ReasonInsufficientStock, ReasonInternal} {
+		CheckoutFailures.WithLabelValues(reason)
+	}
+}
+
+// RegisterDB exposes the connection pool stats of db.
+func RegisterDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
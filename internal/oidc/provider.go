@@ -0,0 +1,120 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gogroceries/config"
+	"gogroceries/domain"
+	"time"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+const requestTimeout = 10 * time.Second
+
+type provider struct {
+	name     string
+	oauth    oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+	oidc     *gooidc.Provider
+}
+
+func NewProvider(cfg config.OIDCProviderConfig) (domain.OIDCProvider, error) {
+	if cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return nil, fmt.Errorf("oidc provider %s needs an issuer, client id and redirect url", cfg.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	discovered, err := gooidc.NewProvider(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("oidc discovery for %s failed: %w", cfg.Name, err)
+	}
+
+	return &provider{
+		name: cfg.Name,
+		oauth: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     discovered.Endpoint(),
+			Scopes:       []string{gooidc.ScopeOpenID, "email", "profile"},
+		},
+		verifier: discovered.Verifier(&gooidc.Config{ClientID: cfg.ClientID}),
+		oidc:     discovered,
+	}, nil
+}
+
+func NewProviders(cfgs []config.OIDCProviderConfig) (map[string]domain.OIDCProvider, error) {
+	providers := map[string]domain.OIDCProvider{}
+	for _, cfg := range cfgs {
+		p, err := NewProvider(cfg)
+		if err != nil {
+			return nil, err
+		}
+		providers[cfg.Name] = p
+	}
+	return providers, nil
+}
+
+func (p *provider) Name() string {
+	return p.name
+}
+
+func (p *provider) AuthCodeURL(state, nonce, codeVerifier string) string {
+	return p.oauth.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier))
+}
+
+func (p *provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.OIDCIdentity, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	token, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("code exchange failed: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("provider did not return an id_token")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("id_token verification failed: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("id_token nonce mismatch")
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("failed to read id_token claims: %w", err)
+	}
+
+	// Some providers only put the email in the userinfo response.
+	if claims.Email == "" {
+		info, err := p.oidc.UserInfo(ctx, oauth2.StaticTokenSource(token))
+		if err == nil && info.Subject == idToken.Subject {
+			claims.Email = info.Email
+			claims.EmailVerified = info.EmailVerified
+			_ = info.Claims(&struct {
+				Name *string `json:"name"`
+			}{&claims.Name})
+		}
+	}
+
+	return &domain.OIDCIdentity{
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"gogroceries/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type postgresAccountRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresAccountRepository(db *gorm.DB) domain.AccountRepository {
+	return &postgresAccountRepository{db}
+}
+
+func (r *postgresAccountRepository) ExportData(ctx context.Context, userID uint) (*domain.UserDataExport, error) {
+	export := &domain.UserDataExport{ExportedAt: time.Now()}
+
+	var user domain.User
+	if err := dbFromContext(ctx, r.db).Preload("Roles").Preload("Toko").First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	export.Profile = &user
+
+	if err := dbFromContext(ctx, r.db).Where("id_user = ?", userID).Order("created_at ASC").Find(&export.Alamat).Error; err != nil {
+		return nil, fmt.Errorf("gagal export alamat: %w", err)
+	}
+
+	err := dbFromContext(ctx, r.db).Preload("AlamatKirim", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("DetailTrx").
+		Preload("DetailTrx.LogProduk").
+		Where("id_user = ?", userID).
+		Order("created_at ASC").
+		Find(&export.Transaksi).Error
+	if err != nil {
+		return nil, fmt.Errorf("gagal export transaksi: %w", err)
+	}
+
+	if err := dbFromContext(ctx, r.db).Preload("Toko").Where("id_user = ?", userID).Find(&export.Memberships).Error; err != nil {
+		return nil, fmt.Errorf("gagal export keanggotaan toko: %w", err)
+	}
+
+	if err := dbFromContext(ctx, r.db).Where("id_user = ?", userID).Find(&export.Identities).Error; err != nil {
+		return nil, fmt.Errorf("gagal export identitas login: %w", err)
+	}
+
+	return export, nil
+}
+
+// Anonymize scrubs personal data and closes the account in one transaction.
+// Orders and their LogProduk snapshots are kept for accounting; the addresses
+// they point to are blanked instead of removed.
+func (r *postgresAccountRepository) Anonymize(ctx context.Context, userID uint, at time.Time) error {
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"nama":          "Deleted User",
+			"kata_sandi":    "",
+			"no_telp":       fmt.Sprintf("deleted-%d", userID),
+			"email":         fmt.Sprintf("deleted-%d@deleted.invalid", userID),
+			"tanggal_lahir": "",
+			"jenis_kelamin": "",
+			"tentang":       "",
+			"pekerjaan":     "",
+			"id_provinsi":   "",
+			"id_kota":       "",
+			"is_admin":      false,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("gagal anonimisasi user: %w", err)
+		}
+
+		err = tx.Unscoped().Model(&domain.Alamat{}).Where("id_user = ?", userID).Updates(map[string]interface{}{
+			"judul_alamat":  "",
+			"nama_penerima": "Deleted User",
+			"no_telp":       "",
+			"detail_alamat": "",
+		}).Error
+		if err != nil {
+			return fmt.Errorf("gagal anonimisasi alamat: %w", err)
+		}
+		if err := tx.Where("id_user = ?", userID).Delete(&domain.Alamat{}).Error; err != nil {
+			return fmt.Errorf("gagal menghapus alamat: %w", err)
+		}
+
+		var tokoIDs []uint
+		if err := tx.Model(&domain.Toko{}).Where("id_user = ?", userID).Pluck("id", &tokoIDs).Error; err != nil {
+			return fmt.Errorf("gagal mencari toko: %w", err)
+		}
+		if len(tokoIDs) > 0 {
+			if err := tx.Where("id_toko IN ?", tokoIDs).Delete(&domain.Produk{}).Error; err != nil {
+				return fmt.Errorf("gagal menonaktifkan produk: %w", err)
+			}
+			if err := tx.Where("id IN ?", tokoIDs).Delete(&domain.Toko{}).Error; err != nil {
+				return fmt.Errorf("gagal menonaktifkan toko: %w", err)
+			}
+			if err := tx.Where("id_toko IN ?", tokoIDs).Delete(&domain.TokoMember{}).Error; err != nil {
+				return fmt.Errorf("gagal menghapus anggota toko: %w", err)
+			}
+			err = tx.Where("id_toko IN ? AND status = ?", tokoIDs, domain.InvitationStatusPending).
+				Delete(&domain.TokoInvitation{}).Error
+			if err != nil {
+				return fmt.Errorf("gagal menghapus undangan toko: %w", err)
+			}
+		}
+
+		err = tx.Model(&domain.ApiKey{}).
+			Where("(id_user = ? OR id_toko IN ?) AND revoked_at IS NULL", userID, append(tokoIDs, 0)).
+			Update("revoked_at", at).Error
+		if err != nil {
+			return fmt.Errorf("gagal mencabut api key: %w", err)
+		}
+
+		if err := tx.Where("id_user = ?", userID).Delete(&domain.TokoMember{}).Error; err != nil {
+			return fmt.Errorf("gagal menghapus keanggotaan toko: %w", err)
+		}
+		if err := tx.Where("id_user = ?", userID).Delete(&domain.UserRole{}).Error; err != nil {
+			return fmt.Errorf("gagal menghapus role: %w", err)
+		}
+		if err := tx.Where("id_user = ?", userID).Delete(&domain.UserIdentity{}).Error; err != nil {
+			return fmt.Errorf("gagal menghapus identitas login: %w", err)
+		}
+
+		return tx.Delete(&domain.User{}, userID).Error
+	})
+}
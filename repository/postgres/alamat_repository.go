@@ -1,7 +1,9 @@
 package postgres
 
 import (
+	"context"
 	"gogroceries/domain"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -14,49 +16,40 @@ func NewPostgresAlamatRepository(db *gorm.DB) domain.AlamatRepository {
 	return &postgresAlamatRepository{db}
 }
 
-func (r *postgresAlamatRepository) Create(alamat *domain.Alamat) error {
-	return r.db.Create(alamat).Error
+func (r *postgresAlamatRepository) Create(ctx context.Context, alamat *domain.Alamat) error {
+	return dbFromContext(ctx, r.db).Create(alamat).Error
 }
 
-func (r *postgresAlamatRepository) Update(alamat *domain.Alamat) error {
-	return r.db.Save(alamat).Error
+func (r *postgresAlamatRepository) Update(ctx context.Context, alamat *domain.Alamat) error {
+	return dbFromContext(ctx, r.db).Save(alamat).Error
 }
 
-func (r *postgresAlamatRepository) Delete(id, userID uint) error {
-	return r.db.Where("id = ? AND id_user = ?", id, userID).Delete(&domain.Alamat{}).Error
+func (r *postgresAlamatRepository) Delete(ctx context.Context, id, userID uint) error {
+	return dbFromContext(ctx, r.db).Where("id = ? AND id_user = ?", id, userID).Delete(&domain.Alamat{}).Error
 }
 
-func (r *postgresAlamatRepository) FindByID(id uint) (*domain.Alamat, error) {
+func (r *postgresAlamatRepository) FindByID(ctx context.Context, id uint) (*domain.Alamat, error) {
 	var alamat domain.Alamat
-	err := r.db.First(&alamat, id).Error
+	err := dbFromContext(ctx, r.db).First(&alamat, id).Error
 	return &alamat, err
 }
 
-func (r *postgresAlamatRepository) FindAllByUserID(userID uint, filter domain.AlamatFilter, offset, limit int) ([]domain.Alamat, int64, error) {
-	var alamats []domain.Alamat
-	var total int64
+var alamatKeyset = newestFirst("alamats", func(a domain.Alamat) (time.Time, uint) { return a.CreatedAt, a.ID })
 
-	query := r.db.Model(&domain.Alamat{}).Where("id_user = ?", userID)
+func (r *postgresAlamatRepository) FindAllByUserID(ctx context.Context, userID uint, filter domain.AlamatFilter, page domain.PageRequest) ([]domain.Alamat, *domain.PageInfo, error) {
+	query := dbFromContext(ctx, r.db).Model(&domain.Alamat{}).Where("id_user = ?", userID)
 
 	if filter.JudulAlamat != "" {
 		query = query.Where("judul_alamat ILIKE ?", "%"+filter.JudulAlamat+"%")
 	}
 
-	err := query.Count(&total).Error
-	if err != nil {
-		return nil, 0, err
-	}
-
-	err = query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&alamats).Error
-	if err != nil {
-		return nil, 0, err
-	}
-
-	return alamats, total, nil
+	return keysetPaginate(query, alamatKeyset, page, func(query *gorm.DB, dest *[]domain.Alamat) error {
+		return query.Find(dest).Error
+	})
 }
 
-func (r *postgresAlamatRepository) FindByIDAndUserID(id uint, userID uint) (*domain.Alamat, error) {
+func (r *postgresAlamatRepository) FindByIDAndUserID(ctx context.Context, id uint, userID uint) (*domain.Alamat, error) {
 	var alamat domain.Alamat
-	err := r.db.Where("id = ? AND id_user = ?", id, userID).First(&alamat).Error
+	err := dbFromContext(ctx, r.db).Where("id = ? AND id_user = ?", id, userID).First(&alamat).Error
 	return &alamat, err
 }
\ No newline at end of file
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"gogroceries/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type postgresApiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresApiKeyRepository(db *gorm.DB) domain.ApiKeyRepository {
+	return &postgresApiKeyRepository{db}
+}
+
+func (r *postgresApiKeyRepository) Create(ctx context.Context, key *domain.ApiKey) error {
+	return dbFromContext(ctx, r.db).Create(key).Error
+}
+
+func (r *postgresApiKeyRepository) Update(ctx context.Context, key *domain.ApiKey) error {
+	return dbFromContext(ctx, r.db).Save(key).Error
+}
+
+func (r *postgresApiKeyRepository) FindByID(ctx context.Context, id uint) (*domain.ApiKey, error) {
+	var key domain.ApiKey
+	err := dbFromContext(ctx, r.db).First(&key, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *postgresApiKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*domain.ApiKey, error) {
+	var key domain.ApiKey
+	err := dbFromContext(ctx, r.db).Where("prefix = ?", prefix).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *postgresApiKeyRepository) FindAllByTokoID(ctx context.Context, tokoID uint) ([]domain.ApiKey, error) {
+	var keys []domain.ApiKey
+	err := dbFromContext(ctx, r.db).Where("id_toko = ?", tokoID).
+		Order("created_at DESC").
+		Find(&keys).Error
+	return keys, err
+}
+
+var apiKeyKeyset = newestFirst("api_keys", func(k domain.ApiKey) (time.Time, uint) { return k.CreatedAt, k.ID })
+
+func (r *postgresApiKeyRepository) FindAllByTokoIDPaged(ctx context.Context, tokoID uint, page domain.PageRequest) ([]domain.ApiKey, *domain.PageInfo, error) {
+	query := dbFromContext(ctx, r.db).Model(&domain.ApiKey{}).Where("id_toko = ?", tokoID)
+	return keysetPaginate(query, apiKeyKeyset, page, func(query *gorm.DB, dest *[]domain.ApiKey) error {
+		return query.Find(dest).Error
+	})
+}
+
+func (r *postgresApiKeyRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	return dbFromContext(ctx, r.db).Model(&domain.ApiKey{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error
+}
@@ -1,8 +1,10 @@
 package postgres
 
 import (
+	"context"
 	"gogroceries/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type postgresCategoryRepository struct {
@@ -13,26 +15,92 @@ func NewPostgresCategoryRepository(db *gorm.DB) domain.CategoryRepository {
 	return &postgresCategoryRepository{db}
 }
 
-func (r *postgresCategoryRepository) Create(category *domain.Category) error {
-	return r.db.Create(category).Error
+func (r *postgresCategoryRepository) Create(ctx context.Context, category *domain.Category) error {
+	return dbFromContext(ctx, r.db).Create(category).Error
 }
 
-func (r *postgresCategoryRepository) Update(category *domain.Category) error {
-	return r.db.Save(category).Error
+func (r *postgresCategoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	return dbFromContext(ctx, r.db).Save(category).Error
 }
 
-func (r *postgresCategoryRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.Category{}, id).Error
+func (r *postgresCategoryRepository) Delete(ctx context.Context, id uint) error {
+	return dbFromContext(ctx, r.db).Delete(&domain.Category{}, id).Error
 }
 
-func (r *postgresCategoryRepository) FindByID(id uint) (*domain.Category, error) {
+func (r *postgresCategoryRepository) FindByID(ctx context.Context, id uint) (*domain.Category, error) {
 	var category domain.Category
-	err := r.db.First(&category, id).Error
+	err := dbFromContext(ctx, r.db).First(&category, id).Error
 	return &category, err
 }
 
-func (r *postgresCategoryRepository) FindAll() ([]domain.Category, error) {
+func (r *postgresCategoryRepository) LockByID(ctx context.Context, id uint) (*domain.Category, error) {
+	var category domain.Category
+	err := dbFromContext(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&category, id).Error
+	return &category, err
+}
+
+func (r *postgresCategoryRepository) FindBySlug(ctx context.Context, slug string) (*domain.Category, error) {
+	var category domain.Category
+	err := dbFromContext(ctx, r.db).Where("slug = ?", slug).First(&category).Error
+	return &category, err
+}
+
+func (r *postgresCategoryRepository) FindAll(ctx context.Context) ([]domain.Category, error) {
 	var categories []domain.Category
-	err := r.db.Find(&categories).Error
+	err := dbFromContext(ctx, r.db).Order("sort_order, nama_category, id").Find(&categories).Error
 	return categories, err
-}
\ No newline at end of file
+}
+
+// categoryKeyset follows the display order of FindAll.
+var categoryKeyset = keyset[domain.Category]{
+	sort:     "display",
+	columns:  []string{"categories.sort_order", "categories.nama_category"},
+	idColumn: "categories.id",
+	key: func(c domain.Category) ([]interface{}, uint) {
+		return []interface{}{c.SortOrder, c.NamaCategory}, c.ID
+	},
+	decode: decoders(decodeInt, decodeString),
+}
+
+func (r *postgresCategoryRepository) FindAllPaged(ctx context.Context, page domain.PageRequest) ([]domain.Category, *domain.PageInfo, error) {
+	query := dbFromContext(ctx, r.db).Model(&domain.Category{})
+	return keysetPaginate(query, categoryKeyset, page, func(query *gorm.DB, dest *[]domain.Category) error {
+		return query.Find(dest).Error
+	})
+}
+
+// categorySubtree selects the id of a category and of all its descendants.
+// UNION rather than UNION ALL stops the recursion even if a cycle slipped in.
+const categorySubtree = `WITH RECURSIVE subtree AS (
+	SELECT id FROM categories WHERE id = ? AND deleted_at IS NULL
+	UNION
+	SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id WHERE c.deleted_at IS NULL
+) SELECT id FROM subtree`
+
+func (r *postgresCategoryRepository) SubtreeIDs(ctx context.Context, id uint) ([]uint, error) {
+	var ids []uint
+	err := dbFromContext(ctx, r.db).Raw(categorySubtree, id).Scan(&ids).Error
+	return ids, err
+}
+
+func (r *postgresCategoryRepository) CountChildren(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	err := dbFromContext(ctx, r.db).Model(&domain.Category{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *postgresCategoryRepository) CountProduk(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	err := dbFromContext(ctx, r.db).Model(&domain.Produk{}).Where("id_category = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *postgresCategoryRepository) ReassignProduk(ctx context.Context, fromID, toID uint) (int64, error) {
+	result := dbFromContext(ctx, r.db).Unscoped().Model(&domain.Produk{}).Where("id_category = ?", fromID).Update("id_category", toID)
+	return result.RowsAffected, result.Error
+}
+
+func (r *postgresCategoryRepository) ReassignChildren(ctx context.Context, fromID, toID uint) (int64, error) {
+	result := dbFromContext(ctx, r.db).Unscoped().Model(&domain.Category{}).Where("parent_id = ?", fromID).Update("parent_id", toID)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS o_auth_states;
+DROP TABLE IF EXISTS user_identities;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS toko_invitations;
+DROP TABLE IF EXISTS toko_members;
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS log_produks;
+DROP TABLE IF EXISTS detail_trxes;
+DROP TABLE IF EXISTS trxes;
+DROP TABLE IF EXISTS foto_produks;
+DROP TABLE IF EXISTS produks;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS alamats;
+DROP TABLE IF EXISTS tokos;
+DROP TABLE IF EXISTS users;
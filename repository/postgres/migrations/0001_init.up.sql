@@ -0,0 +1,233 @@
+-- Baseline schema, equivalent to what AutoMigrate created. IF NOT EXISTS lets
+-- databases that were previously managed by AutoMigrate adopt migrations.
+
+CREATE TABLE IF NOT EXISTS users (
+    id BIGSERIAL PRIMARY KEY,
+    nama VARCHAR(255) NOT NULL,
+    kata_sandi VARCHAR(255) NOT NULL,
+    no_telp VARCHAR(255) NOT NULL,
+    tanggal_lahir VARCHAR(255),
+    jenis_kelamin VARCHAR(255),
+    tentang TEXT,
+    pekerjaan VARCHAR(255),
+    email VARCHAR(255) NOT NULL,
+    id_provinsi VARCHAR(255),
+    id_kota VARCHAR(255),
+    is_admin BOOLEAN DEFAULT false,
+    suspended_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT uni_users_no_telp UNIQUE (no_telp),
+    CONSTRAINT uni_users_email UNIQUE (email)
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_users_suspended_at ON users (suspended_at);
+
+CREATE TABLE IF NOT EXISTS tokos (
+    id BIGSERIAL PRIMARY KEY,
+    id_user BIGINT NOT NULL,
+    nama_toko VARCHAR(255) NOT NULL,
+    url_foto VARCHAR(255),
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT uni_tokos_id_user UNIQUE (id_user),
+    CONSTRAINT fk_users_toko FOREIGN KEY (id_user) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_tokos_deleted_at ON tokos (deleted_at);
+
+CREATE TABLE IF NOT EXISTS alamats (
+    id BIGSERIAL PRIMARY KEY,
+    id_user BIGINT NOT NULL,
+    judul_alamat VARCHAR(255),
+    nama_penerima VARCHAR(255),
+    no_telp VARCHAR(255),
+    detail_alamat TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT fk_users_alamat FOREIGN KEY (id_user) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_alamats_deleted_at ON alamats (deleted_at);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id BIGSERIAL PRIMARY KEY,
+    nama_category VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_nama_category ON categories (nama_category);
+
+CREATE TABLE IF NOT EXISTS produks (
+    id BIGSERIAL PRIMARY KEY,
+    id_toko BIGINT NOT NULL,
+    id_category BIGINT NOT NULL,
+    nama_produk VARCHAR(255) NOT NULL,
+    slug VARCHAR(255),
+    harga_reseller BIGINT,
+    harga_konsumen BIGINT,
+    stok BIGINT NOT NULL DEFAULT 0,
+    deskripsi TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT fk_tokos_produk FOREIGN KEY (id_toko) REFERENCES tokos (id),
+    CONSTRAINT fk_categories_produk FOREIGN KEY (id_category) REFERENCES categories (id)
+);
+CREATE INDEX IF NOT EXISTS idx_produks_deleted_at ON produks (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_produks_id_category ON produks (id_category);
+CREATE INDEX IF NOT EXISTS idx_produks_id_toko ON produks (id_toko);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_produks_slug ON produks (slug);
+
+CREATE TABLE IF NOT EXISTS foto_produks (
+    id BIGSERIAL PRIMARY KEY,
+    id_produk BIGINT NOT NULL,
+    url VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT fk_produks_foto_produk FOREIGN KEY (id_produk) REFERENCES produks (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_foto_produks_deleted_at ON foto_produks (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_foto_produks_id_produk ON foto_produks (id_produk);
+
+CREATE TABLE IF NOT EXISTS trxes (
+    id BIGSERIAL PRIMARY KEY,
+    id_user BIGINT NOT NULL,
+    id_alamat_kirim BIGINT NOT NULL,
+    harga_total BIGINT NOT NULL,
+    kode_invoice VARCHAR(255),
+    method_bayar VARCHAR(255) NOT NULL,
+    status VARCHAR(50) DEFAULT 'pending',
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT fk_users_trx FOREIGN KEY (id_user) REFERENCES users (id),
+    CONSTRAINT fk_trxes_alamat_kirim FOREIGN KEY (id_alamat_kirim) REFERENCES alamats (id)
+);
+CREATE INDEX IF NOT EXISTS idx_trxes_deleted_at ON trxes (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_trxes_id_user ON trxes (id_user);
+CREATE INDEX IF NOT EXISTS idx_trxes_status ON trxes (status);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_trxes_kode_invoice ON trxes (kode_invoice);
+
+CREATE TABLE IF NOT EXISTS detail_trxes (
+    id BIGSERIAL PRIMARY KEY,
+    id_trx BIGINT NOT NULL,
+    id_produk BIGINT NOT NULL,
+    id_toko BIGINT NOT NULL,
+    kuantitas BIGINT NOT NULL,
+    harga_total BIGINT NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT fk_trxes_detail_trx FOREIGN KEY (id_trx) REFERENCES trxes (id) ON DELETE CASCADE,
+    CONSTRAINT fk_detail_trxes_toko FOREIGN KEY (id_toko) REFERENCES tokos (id)
+);
+CREATE INDEX IF NOT EXISTS idx_detail_trxes_deleted_at ON detail_trxes (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_detail_trxes_id_trx ON detail_trxes (id_trx);
+
+CREATE TABLE IF NOT EXISTS log_produks (
+    id BIGSERIAL PRIMARY KEY,
+    id_detail_trx BIGINT NOT NULL,
+    nama_produk VARCHAR(255),
+    slug VARCHAR(255),
+    harga_reseller BIGINT,
+    harga_konsumen BIGINT,
+    deskripsi TEXT,
+    id_category BIGINT,
+    nama_category VARCHAR(255),
+    id_toko BIGINT,
+    nama_toko VARCHAR(255),
+    url_foto_toko VARCHAR(255),
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT fk_detail_trxes_log_produk FOREIGN KEY (id_detail_trx) REFERENCES detail_trxes (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_log_produks_id_detail_trx ON log_produks (id_detail_trx);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    id BIGSERIAL PRIMARY KEY,
+    id_user BIGINT NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ,
+    CONSTRAINT fk_users_roles FOREIGN KEY (id_user) REFERENCES users (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_role ON user_roles (id_user, role);
+
+CREATE TABLE IF NOT EXISTS toko_members (
+    id BIGSERIAL PRIMARY KEY,
+    id_toko BIGINT NOT NULL,
+    id_user BIGINT NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT fk_toko_members_toko FOREIGN KEY (id_toko) REFERENCES tokos (id),
+    CONSTRAINT fk_toko_members_user FOREIGN KEY (id_user) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_toko_members_id_user ON toko_members (id_user);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_toko_member ON toko_members (id_toko, id_user);
+
+CREATE TABLE IF NOT EXISTS toko_invitations (
+    id BIGSERIAL PRIMARY KEY,
+    id_toko BIGINT NOT NULL,
+    email VARCHAR(255),
+    no_telp VARCHAR(255),
+    role VARCHAR(50) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    invited_by BIGINT NOT NULL,
+    expires_at TIMESTAMPTZ,
+    accepted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT fk_toko_invitations_toko FOREIGN KEY (id_toko) REFERENCES tokos (id)
+);
+CREATE INDEX IF NOT EXISTS idx_toko_invitations_email ON toko_invitations (email);
+CREATE INDEX IF NOT EXISTS idx_toko_invitations_id_toko ON toko_invitations (id_toko);
+CREATE INDEX IF NOT EXISTS idx_toko_invitations_no_telp ON toko_invitations (no_telp);
+CREATE INDEX IF NOT EXISTS idx_toko_invitations_status ON toko_invitations (status);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGSERIAL PRIMARY KEY,
+    id_toko BIGINT NOT NULL,
+    id_user BIGINT NOT NULL,
+    nama VARCHAR(255) NOT NULL,
+    prefix VARCHAR(32) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    scopes TEXT,
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_api_keys_id_toko ON api_keys (id_toko);
+CREATE INDEX IF NOT EXISTS idx_api_keys_id_user ON api_keys (id_user);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_prefix ON api_keys (prefix);
+
+CREATE TABLE IF NOT EXISTS user_identities (
+    id BIGSERIAL PRIMARY KEY,
+    id_user BIGINT NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT fk_user_identities_user FOREIGN KEY (id_user) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_identities_id_user ON user_identities (id_user);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_identity_subject ON user_identities (provider, subject);
+
+CREATE TABLE IF NOT EXISTS o_auth_states (
+    id BIGSERIAL PRIMARY KEY,
+    state VARCHAR(255) NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    nonce VARCHAR(255) NOT NULL,
+    code_verifier VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_o_auth_states_expires_at ON o_auth_states (expires_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_o_auth_states_state ON o_auth_states (state);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_produks_nama_produk_trgm;
+DROP INDEX IF EXISTS idx_produks_search_vector;
+DROP TRIGGER IF EXISTS tokos_refresh_produk_search ON tokos;
+DROP TRIGGER IF EXISTS categories_refresh_produk_search ON categories;
+DROP TRIGGER IF EXISTS produks_search_vector ON produks;
+DROP FUNCTION IF EXISTS produks_refresh_search_vector();
+DROP FUNCTION IF EXISTS produks_set_search_vector();
+DROP FUNCTION IF EXISTS produk_search_vector(TEXT, TEXT, BIGINT, BIGINT);
+ALTER TABLE produks DROP COLUMN IF EXISTS search_vector;
+DROP TEXT SEARCH CONFIGURATION IF EXISTS gogroceries_id;
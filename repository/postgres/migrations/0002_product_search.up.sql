@@ -0,0 +1,63 @@
+-- Full-text product search. search_vector covers the product name (weight A),
+-- category and toko name (B) and description (C). Generated columns cannot read
+-- other tables, so triggers keep it current, including when a category or toko
+-- is renamed.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- Indonesian stemming (affixes like me-, ber-, -nya, -kan) on unaccented words.
+CREATE TEXT SEARCH CONFIGURATION gogroceries_id (COPY = pg_catalog.indonesian);
+ALTER TEXT SEARCH CONFIGURATION gogroceries_id
+    ALTER MAPPING FOR asciiword, asciihword, hword_asciipart, word, hword, hword_part
+    WITH unaccent, indonesian_stem;
+
+ALTER TABLE produks ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION produk_search_vector(nama TEXT, deskripsi TEXT, category_id BIGINT, toko_id BIGINT)
+RETURNS tsvector LANGUAGE sql STABLE AS $$
+    SELECT setweight(to_tsvector('gogroceries_id', coalesce(nama, '')), 'A')
+        || setweight(to_tsvector('gogroceries_id', coalesce((SELECT nama_category FROM categories WHERE id = category_id), '')), 'B')
+        || setweight(to_tsvector('gogroceries_id', coalesce((SELECT nama_toko FROM tokos WHERE id = toko_id), '')), 'B')
+        || setweight(to_tsvector('gogroceries_id', coalesce(deskripsi, '')), 'C')
+$$;
+
+CREATE OR REPLACE FUNCTION produks_set_search_vector() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+    NEW.search_vector := produk_search_vector(NEW.nama_produk, NEW.deskripsi, NEW.id_category, NEW.id_toko);
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER produks_search_vector
+    BEFORE INSERT OR UPDATE OF nama_produk, deskripsi, id_category, id_toko ON produks
+    FOR EACH ROW EXECUTE FUNCTION produks_set_search_vector();
+
+CREATE OR REPLACE FUNCTION produks_refresh_search_vector() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'categories' THEN
+        UPDATE produks SET search_vector = produk_search_vector(nama_produk, deskripsi, id_category, id_toko)
+        WHERE id_category = NEW.id;
+    ELSE
+        UPDATE produks SET search_vector = produk_search_vector(nama_produk, deskripsi, id_category, id_toko)
+        WHERE id_toko = NEW.id;
+    END IF;
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER categories_refresh_produk_search
+    AFTER UPDATE OF nama_category ON categories
+    FOR EACH ROW WHEN (OLD.nama_category IS DISTINCT FROM NEW.nama_category)
+    EXECUTE FUNCTION produks_refresh_search_vector();
+
+CREATE TRIGGER tokos_refresh_produk_search
+    AFTER UPDATE OF nama_toko ON tokos
+    FOR EACH ROW WHEN (OLD.nama_toko IS DISTINCT FROM NEW.nama_toko)
+    EXECUTE FUNCTION produks_refresh_search_vector();
+
+UPDATE produks SET search_vector = produk_search_vector(nama_produk, deskripsi, id_category, id_toko);
+
+CREATE INDEX IF NOT EXISTS idx_produks_search_vector ON produks USING GIN (search_vector);
+-- Typo tolerance: trigram similarity on the name.
+CREATE INDEX IF NOT EXISTS idx_produks_nama_produk_trgm ON produks USING GIN (nama_produk gin_trgm_ops);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_detail_trxes_id_produk;
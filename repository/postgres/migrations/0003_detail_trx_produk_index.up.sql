@@ -0,0 +1,2 @@
+-- Units sold per product, used by sort=best_selling.
+CREATE INDEX IF NOT EXISTS idx_detail_trxes_id_produk ON detail_trxes (id_produk);
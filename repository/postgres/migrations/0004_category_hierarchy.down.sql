@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_categories_slug;
+DROP INDEX IF EXISTS idx_categories_parent_id;
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS fk_categories_children;
+ALTER TABLE categories
+    DROP COLUMN IF EXISTS icon_url,
+    DROP COLUMN IF EXISTS sort_order,
+    DROP COLUMN IF EXISTS slug,
+    DROP COLUMN IF EXISTS parent_id;
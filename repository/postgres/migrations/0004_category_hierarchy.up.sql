@@ -0,0 +1,23 @@
+-- Categories form a tree (Sayur > Sayur Daun) and get a unique slug, a display
+-- order and an icon.
+
+ALTER TABLE categories
+    ADD COLUMN IF NOT EXISTS parent_id BIGINT,
+    ADD COLUMN IF NOT EXISTS slug VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS sort_order BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS icon_url VARCHAR(255);
+
+ALTER TABLE categories
+    ADD CONSTRAINT fk_categories_children FOREIGN KEY (parent_id) REFERENCES categories (id);
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
+
+-- Same rules as helper.Slugify; names that slugify alike get their id appended.
+UPDATE categories
+SET slug = trim(both '-' from regexp_replace(lower(nama_category), '[^a-z0-9]+', '-', 'g'));
+UPDATE categories SET slug = 'category' WHERE slug = '';
+UPDATE categories c
+SET slug = c.slug || '-' || c.id
+WHERE EXISTS (SELECT 1 FROM categories o WHERE o.slug = c.slug AND o.id < c.id);
+
+ALTER TABLE categories ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_slug ON categories (slug);
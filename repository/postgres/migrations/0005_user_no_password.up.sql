@@ -0,0 +1,12 @@
+-- Accounts created through social login never chose a password; deleting them
+-- does not ask for one. Existing ones are recognised by the provider:subject
+-- placeholder stored as their phone number.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS no_password BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE users u
+SET no_password = true
+WHERE EXISTS (
+    SELECT 1 FROM user_identities i
+    WHERE i.id_user = u.id AND u.no_telp = i.provider || ':' || i.subject
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_categories_slug;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_slug ON categories (slug);
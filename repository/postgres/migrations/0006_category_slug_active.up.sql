@@ -0,0 +1,5 @@
+-- Deleted categories keep their slug, so only active categories need unique
+-- slugs; otherwise a deleted category blocks creating a new one with its name.
+
+DROP INDEX IF EXISTS idx_categories_slug;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_slug ON categories (slug) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_categories_root_nama;
+DROP INDEX IF EXISTS idx_categories_parent_nama;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_nama_category ON categories (nama_category);
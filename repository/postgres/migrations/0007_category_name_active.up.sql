@@ -0,0 +1,7 @@
+-- Category names only have to be unique among the active children of the same
+-- parent, so a deleted category no longer blocks reusing its name. NULL parents
+-- never collide in a unique index, hence the second index for top-level names.
+
+DROP INDEX IF EXISTS idx_categories_nama_category;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_parent_nama ON categories (parent_id, nama_category) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_root_nama ON categories (nama_category) WHERE parent_id IS NULL AND deleted_at IS NULL;
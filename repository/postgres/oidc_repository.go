@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"gogroceries/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresUserIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresUserIdentityRepository(db *gorm.DB) domain.UserIdentityRepository {
+	return &postgresUserIdentityRepository{db}
+}
+
+func (r *postgresUserIdentityRepository) Create(ctx context.Context, identity *domain.UserIdentity) error {
+	return dbFromContext(ctx, r.db).Create(identity).Error
+}
+
+func (r *postgresUserIdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+	err := dbFromContext(ctx, r.db).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *postgresUserIdentityRepository) FindAllByUserID(ctx context.Context, userID uint) ([]domain.UserIdentity, error) {
+	var identities []domain.UserIdentity
+	err := dbFromContext(ctx, r.db).Where("id_user = ?", userID).Order("created_at ASC").Find(&identities).Error
+	return identities, err
+}
+
+type postgresOAuthStateRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresOAuthStateRepository(db *gorm.DB) domain.OAuthStateRepository {
+	return &postgresOAuthStateRepository{db}
+}
+
+func (r *postgresOAuthStateRepository) Create(ctx context.Context, state *domain.OAuthState) error {
+	return dbFromContext(ctx, r.db).Create(state).Error
+}
+
+// Consume deletes the state row and returns it, so a state can only be redeemed once.
+func (r *postgresOAuthStateRepository) Consume(ctx context.Context, state string) (*domain.OAuthState, error) {
+	var states []domain.OAuthState
+	err := dbFromContext(ctx, r.db).Clauses(clause.Returning{}).
+		Where("state = ?", state).
+		Delete(&states).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &states[0], nil
+}
+
+func (r *postgresOAuthStateRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	return dbFromContext(ctx, r.db).Where("expires_at < ?", before).Delete(&domain.OAuthState{}).Error
+}
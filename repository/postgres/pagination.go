@@ -0,0 +1,254 @@
+package postgres
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"gogroceries/domain"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// pageCursor is the decoded form of the opaque cursors handed to clients. A
+// keyset cursor carries the sort values and id of the row it points at; sorts
+// on computed values (search relevance, best selling) carry an offset instead.
+type pageCursor struct {
+	Sort     string          `json:"s"`
+	Value    json.RawMessage `json:"v,omitempty"`
+	ID       uint            `json:"id,omitempty"`
+	Offset   int             `json:"o,omitempty"`
+	Backward bool            `json:"b,omitempty"`
+}
+
+func encodeCursor(cursor pageCursor) string {
+	payload, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func decodeCursor(token, sort string) (*pageCursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(token)
+	var cursor pageCursor
+	if err == nil {
+		err = json.Unmarshal(payload, &cursor)
+	}
+	if err != nil || cursor.Sort != sort || cursor.Offset < 0 {
+		return nil, invalidCursor()
+	}
+	return &cursor, nil
+}
+
+func invalidCursor() error {
+	return domain.NewValidationError("pagination.cursor_invalid", map[string]string{
+		"cursor": "pagination.cursor_hint",
+	})
+}
+
+// keyset orders a listing by columns and then id, all in the same direction,
+// so a page can continue from the row a cursor points at. key returns one
+// value per column and decode has one decoder per column.
+type keyset[T any] struct {
+	sort     string
+	columns  []string
+	idColumn string
+	desc     bool
+	key      func(row T) (values []interface{}, id uint)
+	decode   []func(raw json.RawMessage) (interface{}, error)
+}
+
+// newestFirst pages table by created_at DESC, the order of most listings.
+func newestFirst[T any](table string, key func(row T) (time.Time, uint)) keyset[T] {
+	return keyset[T]{
+		sort:     "newest",
+		columns:  []string{table + ".created_at"},
+		idColumn: table + ".id",
+		desc:     true,
+		key: func(row T) ([]interface{}, uint) {
+			createdAt, id := key(row)
+			return []interface{}{createdAt}, id
+		},
+		decode: decoders(decodeTime),
+	}
+}
+
+func decoders(decode ...func(raw json.RawMessage) (interface{}, error)) []func(raw json.RawMessage) (interface{}, error) {
+	return decode
+}
+
+func decodeTime(raw json.RawMessage) (interface{}, error) {
+	var value time.Time
+	err := json.Unmarshal(raw, &value)
+	return value, err
+}
+
+func decodeString(raw json.RawMessage) (interface{}, error) {
+	var value string
+	err := json.Unmarshal(raw, &value)
+	return value, err
+}
+
+func decodeInt(raw json.RawMessage) (interface{}, error) {
+	var value int
+	err := json.Unmarshal(raw, &value)
+	return value, err
+}
+
+// countTotal fills info.Total when the request asks for it.
+func countTotal(query *gorm.DB, req domain.PageRequest, info *domain.PageInfo) error {
+	if !req.WithTotal {
+		return nil
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return err
+	}
+	info.Total = &total
+	return nil
+}
+
+// keysetPaginate loads one page of query ordered by ks. Without a cursor it
+// falls back to req.Page, so page numbers keep working and also yield cursors.
+// One extra row is fetched to know whether another page follows.
+func keysetPaginate[T any](query *gorm.DB, ks keyset[T], req domain.PageRequest, find func(query *gorm.DB, dest *[]T) error) ([]T, *domain.PageInfo, error) {
+	info := &domain.PageInfo{}
+	if err := countTotal(query, req, info); err != nil {
+		return nil, nil, err
+	}
+
+	var cursor *pageCursor
+	if req.Cursor != "" {
+		var err error
+		if cursor, err = decodeCursor(req.Cursor, ks.sort); err != nil {
+			return nil, nil, err
+		}
+	}
+	backward := cursor != nil && cursor.Backward
+
+	desc := ks.desc != backward
+	direction, comparison := "ASC", ">"
+	if desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	columns := append(append([]string{}, ks.columns...), ks.idColumn)
+	if cursor != nil {
+		values, err := ks.decodeValues(cursor.Value)
+		if err != nil {
+			return nil, nil, invalidCursor()
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+		condition := fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), comparison, placeholders)
+		query = query.Where(condition, append(values, cursor.ID)...)
+	} else if req.Page > 1 {
+		query = query.Offset((req.Page - 1) * req.Limit)
+	}
+
+	order := make([]string, len(columns))
+	for i, column := range columns {
+		order[i] = column + " " + direction
+	}
+
+	var rows []T
+	err := find(query.Order(strings.Join(order, ", ")).Limit(req.Limit+1), &rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hasMore := len(rows) > req.Limit
+	if hasMore {
+		rows = rows[:req.Limit]
+	}
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	if len(rows) == 0 {
+		return rows, info, nil
+	}
+
+	hasNext, hasPrev := hasMore, cursor != nil || req.Page > 1
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+	if hasNext {
+		info.NextCursor = ks.cursor(rows[len(rows)-1], false)
+	}
+	if hasPrev {
+		info.PrevCursor = ks.cursor(rows[0], true)
+	}
+	return rows, info, nil
+}
+
+// cursor stores a single sort value as is and several as a JSON list.
+func (ks keyset[T]) cursor(row T, backward bool) string {
+	values, id := ks.key(row)
+	var raw []byte
+	if len(values) == 1 {
+		raw, _ = json.Marshal(values[0])
+	} else {
+		raw, _ = json.Marshal(values)
+	}
+	return encodeCursor(pageCursor{Sort: ks.sort, Value: raw, ID: id, Backward: backward})
+}
+
+func (ks keyset[T]) decodeValues(raw json.RawMessage) ([]interface{}, error) {
+	parts := []json.RawMessage{raw}
+	if len(ks.decode) > 1 {
+		if err := json.Unmarshal(raw, &parts); err != nil {
+			return nil, err
+		}
+	}
+	if len(parts) != len(ks.decode) {
+		return nil, fmt.Errorf("cursor has %d values, want %d", len(parts), len(ks.decode))
+	}
+
+	values := make([]interface{}, len(parts))
+	for i, part := range parts {
+		value, err := ks.decode[i](part)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// offsetPaginate pages by OFFSET for orders that have no stable key. The
+// cursors still hide the offset, so clients use both kinds the same way.
+func offsetPaginate[T any](query *gorm.DB, sort string, req domain.PageRequest, find func(query *gorm.DB, dest *[]T) error) ([]T, *domain.PageInfo, error) {
+	info := &domain.PageInfo{}
+	if err := countTotal(query, req, info); err != nil {
+		return nil, nil, err
+	}
+
+	offset := 0
+	if req.Cursor != "" {
+		cursor, err := decodeCursor(req.Cursor, sort)
+		if err != nil {
+			return nil, nil, err
+		}
+		offset = cursor.Offset
+	} else if req.Page > 1 {
+		offset = (req.Page - 1) * req.Limit
+	}
+
+	var rows []T
+	if err := find(query.Offset(offset).Limit(req.Limit+1), &rows); err != nil {
+		return nil, nil, err
+	}
+
+	if len(rows) > req.Limit {
+		rows = rows[:req.Limit]
+		info.NextCursor = encodeCursor(pageCursor{Sort: sort, Offset: offset + req.Limit})
+	}
+	if offset > 0 {
+		prev := offset - req.Limit
+		if prev < 0 {
+			prev = 0
+		}
+		info.PrevCursor = encodeCursor(pageCursor{Sort: sort, Offset: prev})
+	}
+	return rows, info, nil
+}
@@ -1,10 +1,15 @@
 package postgres
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"gogroceries/domain"
+	"html"
+	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type postgresProdukRepository struct {
@@ -15,61 +20,45 @@ func NewPostgresProdukRepository(db *gorm.DB) domain.ProdukRepository {
 	return &postgresProdukRepository{db}
 }
 
-func (r *postgresProdukRepository) Create(produk *domain.Produk, fotoUrls []string) (*domain.Produk, error) {
-	tx := r.db.Begin()
-	if tx.Error != nil {
-		return nil, tx.Error
-	}
-
-	if err := tx.Create(produk).Error; err != nil {
-		tx.Rollback()
-		return nil, err
-	}
+// unscoped preloads an association even when it was soft-deleted. Products
+// keep pointing at their category after it is deleted, and checkout needs it
+// to snapshot the category name.
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
 
-	for _, url := range fotoUrls {
-		foto := domain.FotoProduk{
-			IdProduk: produk.ID,
-			Url:      url,
-		}
-		if err := tx.Create(&foto).Error; err != nil {
-			tx.Rollback()
-			return nil, err
+func (r *postgresProdukRepository) Create(ctx context.Context, produk *domain.Produk, fotoUrls []string) (*domain.Produk, error) {
+	err := dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(produk).Error; err != nil {
+			return err
 		}
-		produk.FotoProduk = append(produk.FotoProduk, foto)
-	}
 
-	if err := tx.Preload("Category").Preload("Toko").First(produk, produk.ID).Error; err != nil {
-		tx.Rollback()
-		return nil, err
-	}
+		for _, url := range fotoUrls {
+			foto := domain.FotoProduk{
+				IdProduk: produk.ID,
+				Url:      url,
+			}
+			if err := tx.Create(&foto).Error; err != nil {
+				return err
+			}
+			produk.FotoProduk = append(produk.FotoProduk, foto)
+		}
 
-	if err := tx.Commit().Error; err != nil {
+		return tx.Preload("Category", unscoped).Preload("Toko").First(produk, produk.ID).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return produk, nil
 }
 
-func (r *postgresProdukRepository) Update(produk *domain.Produk) error {
-	tx := r.db.Begin()
-	if tx.Error != nil {
-		return tx.Error
-	}
-
-	if err := tx.Save(produk).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
-
-	if err := tx.Commit().Error; err != nil {
-		return err
-	}
-
-	return nil
+func (r *postgresProdukRepository) Update(ctx context.Context, produk *domain.Produk) error {
+	return dbFromContext(ctx, r.db).Save(produk).Error
 }
 
-func (r *postgresProdukRepository) Delete(id uint) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+func (r *postgresProdukRepository) Delete(ctx context.Context, id uint) error {
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Where("id_produk = ?", id).Delete(&domain.FotoProduk{}).Error; err != nil {
 			return err
 		}
@@ -77,10 +66,10 @@ func (r *postgresProdukRepository) Delete(id uint) error {
 		return tx.Delete(&domain.Produk{}, id).Error
 	})
 }
-func (r *postgresProdukRepository) FindByID(id uint) (*domain.Produk, error) {
+func (r *postgresProdukRepository) FindByID(ctx context.Context, id uint) (*domain.Produk, error) {
 	var produk domain.Produk
-	err := r.db.Preload("Toko").
-		Preload("Category").
+	err := dbFromContext(ctx, r.db).Preload("Toko").
+		Preload("Category", unscoped).
 		Preload("FotoProduk").
 		First(&produk, id).Error
 
@@ -91,11 +80,11 @@ func (r *postgresProdukRepository) FindByID(id uint) (*domain.Produk, error) {
 	return &produk, err
 }
 
-func (r *postgresProdukRepository) FindBySlug(slug string) (*domain.Produk, error) {
+func (r *postgresProdukRepository) FindBySlug(ctx context.Context, slug string) (*domain.Produk, error) {
 	var produk domain.Produk
-	err := r.db.Where("slug = ?", slug).
+	err := dbFromContext(ctx, r.db).Where("slug = ?", slug).
 		Preload("Toko").
-		Preload("Category").
+		Preload("Category", unscoped).
 		Preload("FotoProduk").
 		First(&produk).Error
 
@@ -106,57 +95,296 @@ func (r *postgresProdukRepository) FindBySlug(slug string) (*domain.Produk, erro
 	return &produk, err
 }
 
-func (r *postgresProdukRepository) FindAll(filter domain.ProdukFilter, offset, limit int) ([]domain.Produk, int64, error) {
-	var produk []domain.Produk
-	var total int64
+// searchConfig is the text search configuration created by the
+// 0002_product_search migration.
+const searchConfig = "gogroceries_id"
+
+// ts_headline marks matches with control characters so the text can be HTML
+// escaped before they are turned into <mark> tags.
+const (
+	headlineNama      = "HighlightAll=true, StartSel=\x01, StopSel=\x02"
+	headlineDeskripsi = "MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter=\" ... \", StartSel=\x01, StopSel=\x02"
+)
+
+var headlineMarks = strings.NewReplacer("\x01", "<mark>", "\x02", "</mark>")
+
+// produkFacet names the filter left out when counting a facet, so a facet is
+// not narrowed by its own selection.
+type produkFacet int
+
+const (
+	facetNone produkFacet = iota
+	facetCategory
+	facetToko
+	facetPrice
+	facetStock
+)
+
+func (r *postgresProdukRepository) filtered(ctx context.Context, filter domain.ProdukFilter, except produkFacet) *gorm.DB {
+	query := dbFromContext(ctx, r.db).Model(&domain.Produk{})
+
+	if filter.Query != "" {
+		// Matches on the search vector, or on a name close enough to survive typos.
+		query = query.Where("(produks.search_vector @@ websearch_to_tsquery(?, ?) OR ? <% produks.nama_produk)", searchConfig, filter.Query, filter.Query)
+	}
+
+	if filter.CategoryID > 0 && except != facetCategory {
+		// A category also matches the products of its sub-categories.
+		query = query.Where("produks.id_category IN ("+categorySubtree+")", filter.CategoryID)
+	}
+
+	if filter.TokoID > 0 && except != facetToko {
+		query = query.Where("produks.id_toko = ?", filter.TokoID)
+	}
+
+	if filter.MinHarga > 0 && except != facetPrice {
+		query = query.Where("produks.harga_konsumen >= ?", filter.MinHarga)
+	}
+
+	if filter.MaxHarga > 0 && except != facetPrice {
+		query = query.Where("produks.harga_konsumen <= ?", filter.MaxHarga)
+	}
+
+	if filter.InStock && except != facetStock {
+		query = query.Where("produks.stok > 0")
+	}
+
+	return query
+}
+
+// produkSort resolves the default sort: relevance for a search, else newest.
+func produkSort(filter domain.ProdukFilter) domain.ProdukSort {
+	if filter.Sort == "" || (filter.Sort == domain.ProdukSortRelevance && filter.Query == "") {
+		if filter.Query != "" {
+			return domain.ProdukSortRelevance
+		}
+		return domain.ProdukSortNewest
+	}
+	return filter.Sort
+}
+
+// produkOrder returns the ORDER BY for sorts paged by offset; id breaks ties so
+// pages stay stable.
+func produkOrder(filter domain.ProdukFilter) clause.OrderBy {
+	expr := clause.Expr{SQL: "produks.created_at DESC, produks.id DESC"}
+	switch produkSort(filter) {
+	case domain.ProdukSortRelevance:
+		expr = clause.Expr{
+			SQL:  "ts_rank_cd(produks.search_vector, websearch_to_tsquery(?, ?)) + word_similarity(?, produks.nama_produk) DESC, produks.created_at DESC, produks.id DESC",
+			Vars: []interface{}{searchConfig, filter.Query, filter.Query},
+		}
+	case domain.ProdukSortPriceAsc:
+		expr = clause.Expr{SQL: "produks.harga_konsumen ASC, produks.id ASC"}
+	case domain.ProdukSortPriceDesc:
+		expr = clause.Expr{SQL: "produks.harga_konsumen DESC, produks.id DESC"}
+	case domain.ProdukSortBestSelling:
+		// Units sold in transactions that were not cancelled.
+		expr = clause.Expr{
+			SQL: "(SELECT COALESCE(SUM(d.kuantitas), 0) FROM detail_trxes d JOIN trxes t ON t.id = d.id_trx " +
+				"WHERE d.id_produk = produks.id AND d.deleted_at IS NULL AND t.deleted_at IS NULL AND t.status <> ?) DESC, produks.id DESC",
+			Vars: []interface{}{domain.TrxStatusCancelled},
+		}
+	}
+	return clause.OrderBy{Expression: expr}
+}
+
+// produkKeysets are the sorts on stored columns, paged by keyset.
+var produkKeysets = map[domain.ProdukSort]keyset[domain.Produk]{
+	domain.ProdukSortNewest: {
+		sort: string(domain.ProdukSortNewest), columns: []string{"produks.created_at"}, idColumn: "produks.id", desc: true,
+		key:    func(p domain.Produk) ([]interface{}, uint) { return []interface{}{p.CreatedAt}, p.ID },
+		decode: decoders(decodeTime),
+	},
+	domain.ProdukSortPriceAsc: {
+		sort: string(domain.ProdukSortPriceAsc), columns: []string{"produks.harga_konsumen"}, idColumn: "produks.id",
+		key:    func(p domain.Produk) ([]interface{}, uint) { return []interface{}{p.HargaKonsumen}, p.ID },
+		decode: decoders(decodeInt),
+	},
+	domain.ProdukSortPriceDesc: {
+		sort: string(domain.ProdukSortPriceDesc), columns: []string{"produks.harga_konsumen"}, idColumn: "produks.id", desc: true,
+		key:    func(p domain.Produk) ([]interface{}, uint) { return []interface{}{p.HargaKonsumen}, p.ID },
+		decode: decoders(decodeInt),
+	},
+}
+
+func (r *postgresProdukRepository) FindAll(ctx context.Context, filter domain.ProdukFilter, req domain.PageRequest) ([]domain.Produk, *domain.PageInfo, error) {
+	query := r.filtered(ctx, filter, facetNone)
+	sort := produkSort(filter)
+
+	if filter.Query != "" {
+		return offsetPaginate(query, string(sort), req, func(query *gorm.DB, dest *[]domain.Produk) error {
+			produk, err := r.search(ctx, query.Order(produkOrder(filter)), filter.Query)
+			*dest = produk
+			return err
+		})
+	}
+
+	find := func(query *gorm.DB, dest *[]domain.Produk) error {
+		return query.Preload("Toko").
+			Preload("Category", unscoped).
+			Preload("FotoProduk").
+			Find(dest).Error
+	}
+	if ks, ok := produkKeysets[sort]; ok {
+		return keysetPaginate(query, ks, req, find)
+	}
+	return offsetPaginate(query, string(sort), req, func(query *gorm.DB, dest *[]domain.Produk) error {
+		return find(query.Order(produkOrder(filter)), dest)
+	})
+}
 
-	query := r.db.Model(&domain.Produk{}).
+// search reads the ids of the matches selected by query (already ordered and
+// limited), then loads the products with their relations and attaches the
+// highlighted name and description.
+func (r *postgresProdukRepository) search(ctx context.Context, query *gorm.DB, q string) ([]domain.Produk, error) {
+	var hits []struct {
+		ID                 uint
+		NamaHighlight      string
+		DeskripsiHighlight string
+	}
+	err := query.
+		Select("produks.id, "+
+			"ts_headline(?, produks.nama_produk, websearch_to_tsquery(?, ?), ?) AS nama_highlight, "+
+			"ts_headline(?, coalesce(produks.deskripsi, ''), websearch_to_tsquery(?, ?), ?) AS deskripsi_highlight",
+			searchConfig, searchConfig, q, headlineNama,
+			searchConfig, searchConfig, q, headlineDeskripsi).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(hits) == 0 {
+		return []domain.Produk{}, nil
+	}
+
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	var found []domain.Produk
+	err = dbFromContext(ctx, r.db).Where("id IN ?", ids).
 		Preload("Toko").
-		Preload("Category").
-		Preload("FotoProduk")
+		Preload("Category", unscoped).
+		Preload("FotoProduk").
+		Find(&found).Error
+	if err != nil {
+		return nil, err
+	}
 
-	if filter.NamaProduk != "" {
-		query = query.Where("LOWER(nama_produk) LIKE LOWER(?)", "%"+filter.NamaProduk+"%")
+	byID := make(map[uint]domain.Produk, len(found))
+	for _, p := range found {
+		byID[p.ID] = p
 	}
+	produk := make([]domain.Produk, 0, len(hits))
+	for _, hit := range hits {
+		p, ok := byID[hit.ID]
+		if !ok {
+			continue
+		}
+		p.Highlight = &domain.ProdukHighlight{
+			NamaProduk: headlineMarks.Replace(html.EscapeString(hit.NamaHighlight)),
+			Deskripsi:  headlineMarks.Replace(html.EscapeString(hit.DeskripsiHighlight)),
+		}
+		produk = append(produk, p)
+	}
+	return produk, nil
+}
 
-	if filter.CategoryID > 0 {
-		query = query.Where("id_category = ?", filter.CategoryID)
+func (r *postgresProdukRepository) Facets(ctx context.Context, filter domain.ProdukFilter) (*domain.ProdukFacets, error) {
+	facets := &domain.ProdukFacets{
+		Categories:  []domain.FacetCount{},
+		Tokos:       []domain.FacetCount{},
+		PriceRanges: make([]domain.PriceRangeFacet, len(domain.ProdukPriceRanges)),
 	}
 
-	if filter.TokoID > 0 {
-		query = query.Where("id_toko = ?", filter.TokoID)
+	err := r.filtered(ctx, filter, facetCategory).
+		Select("categories.id, categories.nama_category AS nama, count(*) AS count").
+		Joins("JOIN categories ON categories.id = produks.id_category AND categories.deleted_at IS NULL").
+		Group("categories.id, categories.nama_category").
+		Order("count DESC, nama ASC").
+		Scan(&facets.Categories).Error
+	if err != nil {
+		return nil, err
 	}
 
-	if filter.MinHarga > 0 {
-		query = query.Where("harga_konsumen >= ?", filter.MinHarga)
+	err = r.filtered(ctx, filter, facetToko).
+		Select("tokos.id, tokos.nama_toko AS nama, count(*) AS count").
+		Joins("JOIN tokos ON tokos.id = produks.id_toko AND tokos.deleted_at IS NULL").
+		Group("tokos.id, tokos.nama_toko").
+		Order("count DESC, nama ASC").
+		Limit(maxTokoFacets).
+		Scan(&facets.Tokos).Error
+	if err != nil {
+		return nil, err
 	}
 
-	if filter.MaxHarga > 0 {
-		query = query.Where("harga_konsumen <= ?", filter.MaxHarga)
+	var buckets []struct {
+		Bucket int
+		Count  int64
+	}
+	bucketSQL, bucketVars := priceBucketCase()
+	err = r.filtered(ctx, filter, facetPrice).
+		Select("("+bucketSQL+") AS bucket, count(*) AS count", bucketVars...).
+		Group("bucket").
+		Scan(&buckets).Error
+	if err != nil {
+		return nil, err
+	}
+	for i, priceRange := range domain.ProdukPriceRanges {
+		facets.PriceRanges[i].PriceRange = priceRange
+	}
+	for _, bucket := range buckets {
+		if bucket.Bucket >= 0 && bucket.Bucket < len(facets.PriceRanges) {
+			facets.PriceRanges[bucket.Bucket].Count = bucket.Count
+		}
 	}
 
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+	err = r.filtered(ctx, filter, facetStock).Where("produks.stok > 0").Count(&facets.InStock).Error
+	if err != nil {
+		return nil, err
 	}
 
-	err := query.Offset(offset).
-		Limit(limit).
-		Order("created_at DESC").
-		Find(&produk).Error
+	return facets, nil
+}
 
-	return produk, total, err
+// maxTokoFacets caps the toko facet to the tokos with the most matches.
+const maxTokoFacets = 20
+
+// priceBucketCase maps harga_konsumen to its index in domain.ProdukPriceRanges.
+func priceBucketCase() (string, []interface{}) {
+	var sql strings.Builder
+	var vars []interface{}
+	sql.WriteString("CASE")
+	for i, priceRange := range domain.ProdukPriceRanges {
+		if priceRange.Max == 0 {
+			sql.WriteString(" WHEN produks.harga_konsumen >= ? THEN ?")
+			vars = append(vars, priceRange.Min, i)
+			continue
+		}
+		sql.WriteString(" WHEN produks.harga_konsumen BETWEEN ? AND ? THEN ?")
+		vars = append(vars, priceRange.Min, priceRange.Max, i)
+	}
+	sql.WriteString(" ELSE -1 END")
+	return sql.String(), vars
 }
 
-func (r *postgresProdukRepository) UpdateStok(tx *gorm.DB, produkID uint, kuantitas int) error {
-	return tx.Model(&domain.Produk{}).
-		Where("id = ?", produkID).
-		Update("stok", gorm.Expr("stok + ?", kuantitas)).
-		Error
+// UpdateStok also reaches soft-deleted produk so a cancelled order can
+// give its stock back after the produk was removed.
+func (r *postgresProdukRepository) UpdateStok(ctx context.Context, produkID uint, kuantitas int) error {
+	result := dbFromContext(ctx, r.db).Unscoped().Model(&domain.Produk{}).
+		Where("id = ? AND stok + ? >= 0", produkID, kuantitas).
+		UpdateColumn("stok", gorm.Expr("stok + ?", kuantitas))
+	if result.Error != nil {
+		return fmt.Errorf("gagal update stok produk ID %d: %w", produkID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.NewInsufficientStockError("produk.stock_insufficient", "id", produkID)
+	}
+	return nil
 }
 
-func (r *postgresProdukRepository) FindFotoByProdukID(produkID uint) ([]domain.FotoProduk, error) {
+func (r *postgresProdukRepository) FindFotoByProdukID(ctx context.Context, produkID uint) ([]domain.FotoProduk, error) {
 	var fotos []domain.FotoProduk
-	err := r.db.Where("id_produk = ?", produkID).
+	err := dbFromContext(ctx, r.db).Where("id_produk = ?", produkID).
 		Find(&fotos).Error
 
 	if err != nil {
@@ -166,11 +394,11 @@ func (r *postgresProdukRepository) FindFotoByProdukID(produkID uint) ([]domain.F
 	return fotos, nil
 }
 
-func (r *postgresProdukRepository) FindByTokoID(tokoID uint) ([]domain.Produk, error) {
+func (r *postgresProdukRepository) FindByTokoID(ctx context.Context, tokoID uint) ([]domain.Produk, error) {
 	var produk []domain.Produk
-	err := r.db.Where("id_toko = ?", tokoID).
+	err := dbFromContext(ctx, r.db).Where("id_toko = ?", tokoID).
 		Preload("Toko").
-		Preload("Category").
+		Preload("Category", unscoped).
 		Preload("FotoProduk").
 		Order("created_at DESC").
 		Find(&produk).Error
@@ -178,11 +406,11 @@ func (r *postgresProdukRepository) FindByTokoID(tokoID uint) ([]domain.Produk, e
 	return produk, err
 }
 
-func (r *postgresProdukRepository) FindByIDs(ids []uint) ([]domain.Produk, error) {
+func (r *postgresProdukRepository) FindByIDs(ctx context.Context, ids []uint) ([]domain.Produk, error) {
 	var produks []domain.Produk
 	
-	err := r.db.Preload("Toko").
-		Preload("Category").
+	err := dbFromContext(ctx, r.db).Preload("Toko").
+		Preload("Category", unscoped).
 		Where("id IN (?)", ids).
 		Find(&produks).Error
 
@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"gogroceries/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type postgresTokoMemberRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresTokoMemberRepository(db *gorm.DB) domain.TokoMemberRepository {
+	return &postgresTokoMemberRepository{db}
+}
+
+func (r *postgresTokoMemberRepository) Create(ctx context.Context, member *domain.TokoMember) error {
+	return dbFromContext(ctx, r.db).Create(member).Error
+}
+
+func (r *postgresTokoMemberRepository) Update(ctx context.Context, member *domain.TokoMember) error {
+	return dbFromContext(ctx, r.db).Save(member).Error
+}
+
+func (r *postgresTokoMemberRepository) Delete(ctx context.Context, tokoID, userID uint) error {
+	return dbFromContext(ctx, r.db).Where("id_toko = ? AND id_user = ?", tokoID, userID).Delete(&domain.TokoMember{}).Error
+}
+
+func (r *postgresTokoMemberRepository) FindByTokoAndUser(ctx context.Context, tokoID, userID uint) (*domain.TokoMember, error) {
+	var member domain.TokoMember
+	err := dbFromContext(ctx, r.db).Where("id_toko = ? AND id_user = ?", tokoID, userID).First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *postgresTokoMemberRepository) FindAllByTokoID(ctx context.Context, tokoID uint) ([]domain.TokoMember, error) {
+	var members []domain.TokoMember
+	err := dbFromContext(ctx, r.db).Preload("User").
+		Where("id_toko = ?", tokoID).
+		Order("created_at ASC").
+		Find(&members).Error
+	return members, err
+}
+
+func (r *postgresTokoMemberRepository) FindAllByUserID(ctx context.Context, userID uint) ([]domain.TokoMember, error) {
+	var members []domain.TokoMember
+	err := dbFromContext(ctx, r.db).Preload("Toko").
+		Where("id_user = ?", userID).
+		Order("created_at ASC").
+		Find(&members).Error
+	return members, err
+}
+
+// Members are listed in the order they joined, like the unpaged listings.
+var tokoMemberKeyset = keyset[domain.TokoMember]{
+	sort:     "joined",
+	columns:  []string{"toko_members.created_at"},
+	idColumn: "toko_members.id",
+	key: func(m domain.TokoMember) ([]interface{}, uint) {
+		return []interface{}{m.CreatedAt}, m.ID
+	},
+	decode: decoders(decodeTime),
+}
+
+func (r *postgresTokoMemberRepository) FindAllByTokoIDPaged(ctx context.Context, tokoID uint, page domain.PageRequest) ([]domain.TokoMember, *domain.PageInfo, error) {
+	query := dbFromContext(ctx, r.db).Model(&domain.TokoMember{}).Where("id_toko = ?", tokoID)
+	return keysetPaginate(query, tokoMemberKeyset, page, func(query *gorm.DB, dest *[]domain.TokoMember) error {
+		return query.Preload("User").Find(dest).Error
+	})
+}
+
+func (r *postgresTokoMemberRepository) FindAllByUserIDPaged(ctx context.Context, userID uint, page domain.PageRequest) ([]domain.TokoMember, *domain.PageInfo, error) {
+	query := dbFromContext(ctx, r.db).Model(&domain.TokoMember{}).Where("id_user = ?", userID)
+	return keysetPaginate(query, tokoMemberKeyset, page, func(query *gorm.DB, dest *[]domain.TokoMember) error {
+		return query.Preload("Toko").Find(dest).Error
+	})
+}
+
+type postgresTokoInvitationRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresTokoInvitationRepository(db *gorm.DB) domain.TokoInvitationRepository {
+	return &postgresTokoInvitationRepository{db}
+}
+
+func (r *postgresTokoInvitationRepository) Create(ctx context.Context, invitation *domain.TokoInvitation) error {
+	return dbFromContext(ctx, r.db).Create(invitation).Error
+}
+
+func (r *postgresTokoInvitationRepository) Update(ctx context.Context, invitation *domain.TokoInvitation) error {
+	return dbFromContext(ctx, r.db).Save(invitation).Error
+}
+
+// MarkAccepted only moves an invitation that is still pending, so two
+// concurrent accepts cannot both add a member.
+func (r *postgresTokoInvitationRepository) MarkAccepted(ctx context.Context, id uint, acceptedAt time.Time) error {
+	result := dbFromContext(ctx, r.db).Model(&domain.TokoInvitation{}).
+		Where("id = ? AND status = ? AND expires_at > ?", id, domain.InvitationStatusPending, acceptedAt).
+		Updates(map[string]interface{}{
+			"status":      domain.InvitationStatusAccepted,
+			"accepted_at": acceptedAt,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("gagal update undangan: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.NewConflictError("toko_member.invitation_expired")
+	}
+	return nil
+}
+
+func (r *postgresTokoInvitationRepository) FindByID(ctx context.Context, id uint) (*domain.TokoInvitation, error) {
+	var invitation domain.TokoInvitation
+	err := dbFromContext(ctx, r.db).Preload("Toko").First(&invitation, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *postgresTokoInvitationRepository) FindPendingByTokoID(ctx context.Context, tokoID uint) ([]domain.TokoInvitation, error) {
+	var invitations []domain.TokoInvitation
+	err := dbFromContext(ctx, r.db).Where("id_toko = ? AND status = ? AND expires_at > ?", tokoID, domain.InvitationStatusPending, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *postgresTokoInvitationRepository) FindPendingByContact(ctx context.Context, email, noTelp string) ([]domain.TokoInvitation, error) {
+	var invitations []domain.TokoInvitation
+	err := dbFromContext(ctx, r.db).Preload("Toko").
+		Where("status = ? AND expires_at > ?", domain.InvitationStatusPending, time.Now()).
+		Where(dbFromContext(ctx, r.db).Where("email <> '' AND LOWER(email) = LOWER(?)", email).Or("no_telp <> '' AND no_telp = ?", noTelp)).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+var tokoInvitationKeyset = newestFirst("toko_invitations", func(i domain.TokoInvitation) (time.Time, uint) { return i.CreatedAt, i.ID })
+
+func (r *postgresTokoInvitationRepository) FindPendingByTokoIDPaged(ctx context.Context, tokoID uint, page domain.PageRequest) ([]domain.TokoInvitation, *domain.PageInfo, error) {
+	query := dbFromContext(ctx, r.db).Model(&domain.TokoInvitation{}).
+		Where("id_toko = ? AND status = ? AND expires_at > ?", tokoID, domain.InvitationStatusPending, time.Now())
+	return keysetPaginate(query, tokoInvitationKeyset, page, func(query *gorm.DB, dest *[]domain.TokoInvitation) error {
+		return query.Find(dest).Error
+	})
+}
+
+func (r *postgresTokoInvitationRepository) FindPendingByContactPaged(ctx context.Context, email, noTelp string, page domain.PageRequest) ([]domain.TokoInvitation, *domain.PageInfo, error) {
+	query := dbFromContext(ctx, r.db).Model(&domain.TokoInvitation{}).
+		Where("status = ? AND expires_at > ?", domain.InvitationStatusPending, time.Now()).
+		Where(dbFromContext(ctx, r.db).Where("email <> '' AND LOWER(email) = LOWER(?)", email).Or("no_telp <> '' AND no_telp = ?", noTelp))
+	return keysetPaginate(query, tokoInvitationKeyset, page, func(query *gorm.DB, dest *[]domain.TokoInvitation) error {
+		return query.Preload("Toko").Find(dest).Error
+	})
+}
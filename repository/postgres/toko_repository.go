@@ -1,7 +1,9 @@
 package postgres
 
 import (
+	"context"
 	"gogroceries/domain"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -14,59 +16,50 @@ func NewPostgresTokoRepository(db *gorm.DB) domain.TokoRepository {
 	return &postgresTokoRepository{db}
 }
 
-func (r *postgresTokoRepository) Create(toko *domain.Toko) error {
-	return r.db.Create(toko).Error
+func (r *postgresTokoRepository) Create(ctx context.Context, toko *domain.Toko) error {
+	return dbFromContext(ctx, r.db).Create(toko).Error
 }
 
-func (r *postgresTokoRepository) FindByUserID(userID uint) (*domain.Toko, error) {
+func (r *postgresTokoRepository) FindByUserID(ctx context.Context, userID uint) (*domain.Toko, error) {
 	var toko domain.Toko
-	err := r.db.Where("id_user = ?", userID).First(&toko).Error
+	err := dbFromContext(ctx, r.db).Where("id_user = ?", userID).First(&toko).Error
 	if err != nil {
 		return nil, err
 	}
 	return &toko, nil
 }
 
-func (r *postgresTokoRepository) FindByID(id uint) (*domain.Toko, error) {
+func (r *postgresTokoRepository) FindByID(ctx context.Context, id uint) (*domain.Toko, error) {
     var toko domain.Toko
-    err := r.db.First(&toko, id).Error
+    err := dbFromContext(ctx, r.db).First(&toko, id).Error
     if err != nil {
         return nil, err
     }
     return &toko, nil
 }
 
-func (r *postgresTokoRepository) Update(toko *domain.Toko) error {
-	return r.db.Save(toko).Error
+func (r *postgresTokoRepository) Update(ctx context.Context, toko *domain.Toko) error {
+	return dbFromContext(ctx, r.db).Save(toko).Error
 }
 
-func (r *postgresTokoRepository) Delete(toko *domain.Toko) error {
-	return r.db.Delete(toko).Error
+func (r *postgresTokoRepository) Delete(ctx context.Context, toko *domain.Toko) error {
+	return dbFromContext(ctx, r.db).Delete(toko).Error
 }
 
-func (r *postgresTokoRepository) FindAll(filter domain.TokoFilter, offset, limit int) ([]domain.Toko, int64, error) {
-	var tokos []domain.Toko
-	var total int64
+var tokoKeyset = newestFirst("tokos", func(t domain.Toko) (time.Time, uint) { return t.CreatedAt, t.ID })
 
-	query := r.db.Model(&domain.Toko{})
+func (r *postgresTokoRepository) FindAll(ctx context.Context, filter domain.TokoFilter, page domain.PageRequest) ([]domain.Toko, *domain.PageInfo, error) {
+	query := dbFromContext(ctx, r.db).Model(&domain.Toko{})
 
 	if filter.NamaToko != "" {
 		query = query.Where("nama_toko ILIKE ?", "%"+filter.NamaToko+"%")
 	}
 
-	err := query.Count(&total).Error
-	if err != nil {
-		return nil, 0, err
-	}
-
-	err = query.Offset(offset).Limit(limit).Find(&tokos).Error
-	if err != nil {
-		return nil, 0, err
-	}
-
-	return tokos, total, nil
+	return keysetPaginate(query, tokoKeyset, page, func(query *gorm.DB, dest *[]domain.Toko) error {
+		return query.Find(dest).Error
+	})
 }
 
-func (r *postgresTokoRepository) FindByIDToko(id uint) (*domain.Toko, error) {
-    return r.FindByID(id)
+func (r *postgresTokoRepository) FindByIDToko(ctx context.Context, id uint) (*domain.Toko, error) {
+    return r.FindByID(ctx, id)
 }
\ No newline at end of file
@@ -1,9 +1,12 @@
 package postgres
 
 import (
+	"context"
 	"errors" 
 	"fmt"    
 	"gogroceries/domain"
+	"gogroceries/internal/logger"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -16,8 +19,8 @@ func NewPostgresTrxRepository(db *gorm.DB) domain.TrxRepository {
 	return &postgresTrxRepository{db}
 }
 
-func (r *postgresTrxRepository) Create(trx *domain.Trx, details []domain.DetailTrx, logs []domain.LogProduk) (*domain.Trx, error) {
-	err := r.db.Transaction(func(tx *gorm.DB) error {
+func (r *postgresTrxRepository) Create(ctx context.Context, trx *domain.Trx, details []domain.DetailTrx, logs []domain.LogProduk) (*domain.Trx, error) {
+	err := dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(trx).Error; err != nil {
 			return fmt.Errorf("gagal simpan trx: %w", err)
 		}
@@ -38,16 +41,6 @@ func (r *postgresTrxRepository) Create(trx *domain.Trx, details []domain.DetailT
 			if err := tx.Create(&logs[i]).Error; err != nil {
 				return fmt.Errorf("gagal simpan log produk ID %d: %w", details[i].IdProduk, err)
 			}
-
-			result := tx.Model(&domain.Produk{}).Where("id = ? AND stok >= ?", details[i].IdProduk, details[i].Kuantitas).
-				UpdateColumn("stok", gorm.Expr("stok - ?", details[i].Kuantitas))
-
-			if result.Error != nil {
-				return fmt.Errorf("gagal update stok produk ID %d: %w", details[i].IdProduk, result.Error)
-			}
-			if result.RowsAffected == 0 {
-				return fmt.Errorf("stok produk ID %d tidak mencukupi saat update", details[i].IdProduk)
-			}
 		}
 
 		return nil 
@@ -57,35 +50,36 @@ func (r *postgresTrxRepository) Create(trx *domain.Trx, details []domain.DetailT
 		return nil, err 
 	}
 
-	err = r.db.Preload("AlamatKirim").
+	err = dbFromContext(ctx, r.db).Preload("AlamatKirim").
 		Preload("DetailTrx").
 		Preload("DetailTrx.LogProduk").
 		Preload("DetailTrx.Toko").
 		First(&trx, trx.ID).Error
 
 	if err != nil {
-		fmt.Printf("Warning: gagal preload data trx setelah create: %v", err)
+		logger.FromContext(ctx).Warn("failed to preload trx after create", "trx_id", trx.ID, "error", err)
 	}
 
 	return trx, nil
 }
 
-func (r *postgresTrxRepository) FindByID(id uint, userID uint) (*domain.Trx, error) {
+func (r *postgresTrxRepository) FindByID(ctx context.Context, id uint) (*domain.Trx, error) {
 	var trx domain.Trx
-	err := r.db.Preload("AlamatKirim").
+	err := dbFromContext(ctx, r.db).Preload("AlamatKirim").
 		Preload("DetailTrx").
 		Preload("DetailTrx.LogProduk").
 		Preload("DetailTrx.Toko").
-		Where("id = ? AND id_user = ?", id, userID). 
 		First(&trx, id).Error
-	return &trx, err
+	if err != nil {
+		return nil, err
+	}
+	return &trx, nil
 }
 
-func (r *postgresTrxRepository) FindAllByUserID(userID uint, filter domain.TrxFilter, limit, offset int) ([]domain.Trx, int64, error) {
-	var trxs []domain.Trx
-	var total int64
+var trxKeyset = newestFirst("trxes", func(t domain.Trx) (time.Time, uint) { return t.CreatedAt, t.ID })
 
-	query := r.db.Model(&domain.Trx{}).Where("id_user = ?", userID)
+func (r *postgresTrxRepository) FindAllByUserID(ctx context.Context, userID uint, filter domain.TrxFilter, page domain.PageRequest) ([]domain.Trx, *domain.PageInfo, error) {
+	query := dbFromContext(ctx, r.db).Model(&domain.Trx{}).Where("id_user = ?", userID)
 
 	if filter.KodeInvoice != "" {
 		query = query.Where("kode_invoice ILIKE ?", "%"+filter.KodeInvoice+"%")
@@ -94,41 +88,94 @@ func (r *postgresTrxRepository) FindAllByUserID(userID uint, filter domain.TrxFi
 		query = query.Where("status = ?", filter.Status)
 	}
 
-	err := query.Count(&total).Error
-	if err != nil {
-		return nil, 0, err
+	return keysetPaginate(query, trxKeyset, page, func(query *gorm.DB, dest *[]domain.Trx) error {
+		return query.Preload("AlamatKirim").
+			Preload("DetailTrx").
+			Preload("DetailTrx.LogProduk").
+			Preload("DetailTrx.Toko").
+			Find(dest).Error
+	})
+}
+
+func (r *postgresTrxRepository) FindAll(ctx context.Context, filter domain.TrxFilter, page domain.PageRequest) ([]domain.Trx, *domain.PageInfo, error) {
+	query := dbFromContext(ctx, r.db).Model(&domain.Trx{})
+
+	if filter.KodeInvoice != "" {
+		query = query.Where("kode_invoice ILIKE ?", "%"+filter.KodeInvoice+"%")
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
 	}
 
-	err = query.Preload("AlamatKirim").
+	return keysetPaginate(query, trxKeyset, page, func(query *gorm.DB, dest *[]domain.Trx) error {
+		return query.Preload("AlamatKirim").
+			Preload("DetailTrx").
+			Preload("DetailTrx.LogProduk").
+			Preload("DetailTrx.Toko").
+			Find(dest).Error
+	})
+}
+
+func (r *postgresTrxRepository) FindByIDAndUserID(ctx context.Context, id uint, userID uint) (*domain.Trx, error) {
+	var trx domain.Trx
+	err := dbFromContext(ctx, r.db).Preload("AlamatKirim").
 		Preload("DetailTrx").
 		Preload("DetailTrx.LogProduk").
 		Preload("DetailTrx.Toko").
-		Limit(limit).Offset(offset).Order("created_at DESC").Find(&trxs).Error
-
-	return trxs, total, err
+		Where("id = ? AND id_user = ?", id, userID).
+		First(&trx).Error
+	if err != nil {
+		return nil, err
+	}
+	return &trx, nil
 }
 
-func (r *postgresTrxRepository) FindByIDAndUserID(id uint, userID uint) (*domain.Trx, error) {
-	return r.FindByID(id, userID) 
+// UpdateStatus only moves a trx that is still in status from, so two
+// concurrent updates cannot both apply their side effects.
+func (r *postgresTrxRepository) UpdateStatus(ctx context.Context, id uint, from, to string) error {
+	result := dbFromContext(ctx, r.db).Model(&domain.Trx{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return fmt.Errorf("gagal update status transaksi: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.NewConflictError("trx.status_changed")
+	}
+	return nil
 }
 
-func (r *postgresTrxRepository) UpdateStatus(id uint, status string) error {
-	return r.db.Model(&domain.Trx{}).
-		Where("id = ?", id).
-		Update("status", status).
-		Error
+func (r *postgresTrxRepository) FindAllByTokoID(ctx context.Context, tokoID uint, filter domain.TrxFilter, page domain.PageRequest) ([]domain.Trx, *domain.PageInfo, error) {
+	tokoTrxIDs := dbFromContext(ctx, r.db).Model(&domain.DetailTrx{}).Select("id_trx").Where("id_toko = ?", tokoID)
+	query := dbFromContext(ctx, r.db).Model(&domain.Trx{}).Where("id IN (?)", tokoTrxIDs)
+
+	if filter.KodeInvoice != "" {
+		query = query.Where("kode_invoice ILIKE ?", "%"+filter.KodeInvoice+"%")
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	return keysetPaginate(query, trxKeyset, page, func(query *gorm.DB, dest *[]domain.Trx) error {
+		return query.Preload("AlamatKirim").
+			Preload("DetailTrx", "id_toko = ?", tokoID).
+			Preload("DetailTrx.LogProduk").
+			Preload("DetailTrx.Toko").
+			Find(dest).Error
+	})
 }
 
-func (r *postgresTrxRepository) FindByTokoID(tokoID uint) ([]domain.Trx, error) {
-	var trx []domain.Trx
-	err := r.db.Joins("JOIN detail_trx ON trx.id = detail_trx.id_trx").
-		Joins("JOIN produk ON detail_trx.id_produk = produk.id").
-		Preload("User").
-		Preload("AlamatKirim"). 
+func (r *postgresTrxRepository) FindByIDAndTokoID(ctx context.Context, id uint, tokoID uint) (*domain.Trx, error) {
+	var trx domain.Trx
+	tokoTrxIDs := dbFromContext(ctx, r.db).Model(&domain.DetailTrx{}).Select("id_trx").Where("id_toko = ?", tokoID)
+	err := dbFromContext(ctx, r.db).Preload("AlamatKirim").
 		Preload("DetailTrx").
-		Preload("DetailTrx.LogProduk"). 
-		Where("detail_trx.id_toko = ?", tokoID). 
-		Distinct("trx.*").
-		Find(&trx).Error
-	return trx, err
-}
\ No newline at end of file
+		Preload("DetailTrx.LogProduk").
+		Preload("DetailTrx.Toko").
+		Where("id = ? AND id IN (?)", id, tokoTrxIDs).
+		First(&trx).Error
+	if err != nil {
+		return nil, err
+	}
+	return &trx, nil
+}
@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"gogroceries/domain"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+type gormTxManager struct {
+	db *gorm.DB
+}
+
+func NewTxManager(db *gorm.DB) domain.TxManager {
+	return &gormTxManager{db}
+}
+
+func (m *gormTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return dbFromContext(ctx, m.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// dbFromContext returns the transaction started by WithinTx when ctx carries
+// one, otherwise db. Either way queries run with ctx for cancellation and tracing.
+func dbFromContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
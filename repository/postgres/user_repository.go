@@ -1,7 +1,9 @@
 package postgres
 
 import (
+	"context"
 	"gogroceries/domain"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -14,13 +16,13 @@ func NewPostgresUserRepository(db *gorm.DB) domain.UserRepository {
 	return &postgresUserRepository{db}
 }
 
-func (r *postgresUserRepository) Create(user *domain.User) error {
-	return r.db.Create(user).Error
+func (r *postgresUserRepository) Create(ctx context.Context, user *domain.User) error {
+	return dbFromContext(ctx, r.db).Create(user).Error
 }
 
-func (r *postgresUserRepository) FindByEmail(email string) (*domain.User, error) {
+func (r *postgresUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
-	err := r.db.Where("email = ?", email).First(&user).Error
+	err := dbFromContext(ctx, r.db).Where("email = ?", email).First(&user).Error
 	
 	if err != nil {
 		return nil, err
@@ -29,37 +31,50 @@ func (r *postgresUserRepository) FindByEmail(email string) (*domain.User, error)
 	return &user, nil
 }
 
-func (r *postgresUserRepository) FindByNoTelp(noTelp string) (*domain.User, error) {
+func (r *postgresUserRepository) FindByNoTelp(ctx context.Context, noTelp string) (*domain.User, error) {
 	var user domain.User
-	err := r.db.Where("no_telp = ?", noTelp).First(&user).Error
+	err := dbFromContext(ctx, r.db).Where("no_telp = ?", noTelp).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *postgresUserRepository) FindById(id uint) (*domain.User, error) {
+func (r *postgresUserRepository) FindById(ctx context.Context, id uint) (*domain.User, error) {
 	var user domain.User
-	err := r.db.First(&user, id).Error
+	err := dbFromContext(ctx, r.db).First(&user, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *postgresUserRepository) Update(user *domain.User) error {
-	return r.db.Save(user).Error
+func (r *postgresUserRepository) Update(ctx context.Context, user *domain.User) error {
+	return dbFromContext(ctx, r.db).Save(user).Error
 }
 
-func (r *postgresUserRepository) Delete(user *domain.User) error {
-	return r.db.Delete(user).Error
+func (r *postgresUserRepository) Delete(ctx context.Context, user *domain.User) error {
+	return dbFromContext(ctx, r.db).Delete(user).Error
 }
 
-func (r *postgresUserRepository) FindAll() ([]domain.User, error) {
-	var users []domain.User
-	err := r.db.Find(&users).Error
-	if err != nil {
-		return nil, err
+var userKeyset = newestFirst("users", func(u domain.User) (time.Time, uint) { return u.CreatedAt, u.ID })
+
+func (r *postgresUserRepository) FindAll(ctx context.Context, filter domain.UserFilter, page domain.PageRequest) ([]domain.User, *domain.PageInfo, error) {
+	query := dbFromContext(ctx, r.db).Model(&domain.User{})
+
+	if filter.Query != "" {
+		like := "%" + filter.Query + "%"
+		query = query.Where("nama ILIKE ? OR email ILIKE ? OR no_telp ILIKE ?", like, like, like)
 	}
-	return users, nil
+
+	switch filter.Status {
+	case domain.UserStatusActive:
+		query = query.Where("suspended_at IS NULL")
+	case domain.UserStatusSuspended:
+		query = query.Where("suspended_at IS NOT NULL")
+	}
+
+	return keysetPaginate(query, userKeyset, page, func(query *gorm.DB, dest *[]domain.User) error {
+		return query.Find(dest).Error
+	})
 }
@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"gogroceries/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresUserRoleRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresUserRoleRepository(db *gorm.DB) domain.UserRoleRepository {
+	return &postgresUserRoleRepository{db}
+}
+
+func (r *postgresUserRoleRepository) FindByUserID(ctx context.Context, userID uint) ([]domain.UserRole, error) {
+	var roles []domain.UserRole
+	err := dbFromContext(ctx, r.db).Where("id_user = ?", userID).Order("role ASC").Find(&roles).Error
+	return roles, err
+}
+
+func (r *postgresUserRoleRepository) Create(ctx context.Context, userRole *domain.UserRole) error {
+	return dbFromContext(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(userRole).Error
+}
+
+func (r *postgresUserRoleRepository) Delete(ctx context.Context, userID uint, role domain.Role) error {
+	return dbFromContext(ctx, r.db).Where("id_user = ? AND role = ?", userID, role).Delete(&domain.UserRole{}).Error
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gogroceries/domain"
+
+	"gorm.io/gorm"
+)
+
+type actorProvider struct {
+	userRepo     domain.UserRepository
+	userRoleRepo domain.UserRoleRepository
+	tokoRepo     domain.TokoRepository
+	memberRepo   domain.TokoMemberRepository
+}
+
+func NewActorProvider(ur domain.UserRepository, urr domain.UserRoleRepository, tr domain.TokoRepository, tmr domain.TokoMemberRepository) domain.ActorProvider {
+	return &actorProvider{
+		userRepo:     ur,
+		userRoleRepo: urr,
+		tokoRepo:     tr,
+		memberRepo:   tmr,
+	}
+}
+
+func (p *actorProvider) Actor(ctx context.Context, userID uint) (*domain.Actor, error) {
+	user, err := p.userRepo.FindById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NewNotFoundError("user.not_found")
+		}
+		return nil, err
+	}
+
+	roles, err := loadUserRoles(ctx, p.userRoleRepo, user)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat role user: %w", err)
+	}
+
+	memberships, err := p.memberRepo.FindAllByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat keanggotaan toko: %w", err)
+	}
+
+	// Tokos created before memberships existed are still owned through Toko.IdUser.
+	owned, err := p.tokoRepo.FindByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("gagal mencari toko: %w", err)
+	}
+	if owned != nil {
+		found := false
+		for _, m := range memberships {
+			if m.IdToko == owned.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			memberships = append(memberships, domain.TokoMember{IdToko: owned.ID, IdUser: userID, Role: domain.TokoRoleOwner, Toko: owned})
+		}
+	}
+
+	return &domain.Actor{
+		UserID:      userID,
+		Roles:       roles,
+		Memberships: memberships,
+	}, nil
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gogroceries/domain"
+	"gogroceries/internal/helper"
+	"gogroceries/internal/tracing"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type adminUsecase struct {
+	userRepo     domain.UserRepository
+	userRoleRepo domain.UserRoleRepository
+}
+
+func NewAdminUsecase(ur domain.UserRepository, urr domain.UserRoleRepository) domain.AdminUsecase {
+	return &adminUsecase{
+		userRepo:     ur,
+		userRoleRepo: urr,
+	}
+}
+
+func (uc *adminUsecase) ListUsers(ctx context.Context, filter domain.UserFilter, page domain.PageRequest) (*domain.Page[domain.User], error) {
+	ctx, span := tracing.Start(ctx, "adminUsecase.ListUsers")
+	defer span.End()
+
+	page = normalizePage(page)
+	users, info, err := uc.userRepo.FindAll(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(page, info, users), nil
+}
+
+func (uc *adminUsecase) GetUser(ctx context.Context, id uint) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "adminUsecase.GetUser")
+	defer span.End()
+
+	user, err := uc.userRepo.FindById(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NewNotFoundError("user.not_found")
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (uc *adminUsecase) SuspendUser(ctx context.Context, id uint, adminID uint) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "adminUsecase.SuspendUser")
+	defer span.End()
+
+	if id == adminID {
+		return nil, domain.NewForbiddenError("admin.cannot_suspend_self")
+	}
+
+	user, err := uc.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsSuspended() {
+		return user, nil
+	}
+
+	now := time.Now()
+	user.SuspendedAt = &now
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("gagal suspend user: %w", err)
+	}
+	return user, nil
+}
+
+func (uc *adminUsecase) ReactivateUser(ctx context.Context, id uint) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "adminUsecase.ReactivateUser")
+	defer span.End()
+
+	user, err := uc.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsSuspended() {
+		return user, nil
+	}
+
+	user.SuspendedAt = nil
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("gagal mengaktifkan user: %w", err)
+	}
+	return user, nil
+}
+
+func (uc *adminUsecase) DeleteUser(ctx context.Context, id uint, adminID uint) error {
+	ctx, span := tracing.Start(ctx, "adminUsecase.DeleteUser")
+	defer span.End()
+
+	if id == adminID {
+		return domain.NewForbiddenError("admin.cannot_delete_self")
+	}
+
+	user, err := uc.GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	return uc.userRepo.Delete(ctx, user)
+}
+
+func (uc *adminUsecase) CreateAdmin(ctx context.Context, req *domain.CreateAdminRequest) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "adminUsecase.CreateAdmin")
+	defer span.End()
+
+	existingUser, err := uc.userRepo.FindByEmail(ctx, req.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("failed to check email")
+	}
+	if existingUser != nil {
+		return nil, domain.NewConflictError("user.email_taken")
+	}
+
+	existingUser, err = uc.userRepo.FindByNoTelp(ctx, req.NoTelp)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("failed to check phone number")
+	}
+	if existingUser != nil {
+		return nil, domain.NewConflictError("user.phone_taken")
+	}
+
+	hashedPassword, err := helper.HashPassword(req.KataSandi)
+	if err != nil {
+		return nil, errors.New("failed to hash password")
+	}
+
+	admin := &domain.User{
+		Nama:      req.Nama,
+		KataSandi: hashedPassword,
+		NoTelp:    req.NoTelp,
+		Email:     req.Email,
+		IsAdmin:   true,
+	}
+	if err := uc.userRepo.Create(ctx, admin); err != nil {
+		return nil, fmt.Errorf("failed to create admin: %w", err)
+	}
+
+	if err := uc.userRoleRepo.Create(ctx, &domain.UserRole{IdUser: admin.ID, Role: domain.RoleAdmin}); err != nil {
+		return nil, fmt.Errorf("failed to grant admin role: %w", err)
+	}
+
+	admin.KataSandi = ""
+	return admin, nil
+}
+
+func (uc *adminUsecase) PromoteAdmin(ctx context.Context, identifier string) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "adminUsecase.PromoteAdmin")
+	defer span.End()
+
+	var user *domain.User
+	var err error
+	if strings.Contains(identifier, "@") {
+		user, err = uc.userRepo.FindByEmail(ctx, identifier)
+	} else {
+		user, err = uc.userRepo.FindByNoTelp(ctx, identifier)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NewNotFoundError("user.not_found")
+		}
+		return nil, err
+	}
+
+	if !user.IsAdmin {
+		user.IsAdmin = true
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return nil, fmt.Errorf("failed to promote user: %w", err)
+		}
+	}
+
+	if err := uc.userRoleRepo.Create(ctx, &domain.UserRole{IdUser: user.ID, Role: domain.RoleAdmin}); err != nil {
+		return nil, fmt.Errorf("failed to grant admin role: %w", err)
+	}
+
+	user.KataSandi = ""
+	return user, nil
+}
@@ -1,23 +1,30 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"gogroceries/domain"
+	"gogroceries/internal/tracing"
 
 	"gorm.io/gorm"
 )
 
 type alamatUsecase struct {
 	alamatRepo domain.AlamatRepository
+	actors     domain.ActorProvider
 }
 
-func NewAlamatUsecase(ar domain.AlamatRepository) domain.AlamatUsecase {
+func NewAlamatUsecase(ar domain.AlamatRepository, ap domain.ActorProvider) domain.AlamatUsecase {
 	return &alamatUsecase{
 		alamatRepo: ar,
+		actors:     ap,
 	}
 }
 
-func (uc *alamatUsecase) CreateAlamat(req *domain.CreateAlamatRequest, userID uint) (*domain.Alamat, error) {
+func (uc *alamatUsecase) CreateAlamat(ctx context.Context, req *domain.CreateAlamatRequest, userID uint) (*domain.Alamat, error) {
+	ctx, span := tracing.Start(ctx, "alamatUsecase.CreateAlamat")
+	defer span.End()
+
 
 	newAlamat := &domain.Alamat{
 		IdUser:       userID,
@@ -27,7 +34,7 @@ func (uc *alamatUsecase) CreateAlamat(req *domain.CreateAlamatRequest, userID ui
 		DetailAlamat: req.DetailAlamat,
 	}
 
-	err := uc.alamatRepo.Create(newAlamat)
+	err := uc.alamatRepo.Create(ctx, newAlamat)
 	if err != nil {
 		return nil, err
 	}
@@ -35,50 +42,37 @@ func (uc *alamatUsecase) CreateAlamat(req *domain.CreateAlamatRequest, userID ui
 	return newAlamat, nil
 }
 
-func (uc *alamatUsecase) GetAllAlamatUser(userID uint, filter domain.AlamatFilter, page, limit int) ([]domain.Alamat, *domain.PaginationResponse, error) {
-	if page <= 0 {
-		page = 1
-	}
-	if limit <= 0 {
-		limit = 10
-	}
-	offset := (page - 1) * limit
+func (uc *alamatUsecase) GetAllAlamatUser(ctx context.Context, userID uint, filter domain.AlamatFilter, page domain.PageRequest) (*domain.Page[domain.Alamat], error) {
+	ctx, span := tracing.Start(ctx, "alamatUsecase.GetAllAlamatUser")
+	defer span.End()
 
-	alamats, totalData, err := uc.alamatRepo.FindAllByUserID(userID, filter, offset, limit)
+	page = normalizePage(page)
+	alamats, info, err := uc.alamatRepo.FindAllByUserID(ctx, userID, filter, page)
 	if err != nil {
-		return nil, nil, err
-	}
-
-	totalPages := (int(totalData) + limit - 1) / limit
-
-	pagination := &domain.PaginationResponse{
-		Page:      page,
-		Limit:     limit,
-		TotalData: int(totalData),
-		TotalPage: totalPages,
+		return nil, err
 	}
 
-	return alamats, pagination, nil
+	return newPage(page, info, alamats), nil
 }
 
-func (uc *alamatUsecase) GetAlamatByID(id uint, userID uint) (*domain.Alamat, error) {	
-	alamat, err := uc.alamatRepo.FindByIDAndUserID(id, userID)
+func (uc *alamatUsecase) GetAlamatByID(ctx context.Context, id uint, userID uint) (*domain.Alamat, error) {	
+	ctx, span := tracing.Start(ctx, "alamatUsecase.GetAlamatByID")
+	defer span.End()
+
+	alamat, err := uc.findOwnAlamat(ctx, id, userID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("alamat tidak ditemukan")
-		}
 		return nil, err
 	}
 
 	return alamat, nil
 }
 
-func (uc *alamatUsecase) UpdateAlamat(id uint, req *domain.UpdateAlamatRequest, userID uint) (*domain.Alamat, error) {	
-	alamat, err := uc.alamatRepo.FindByIDAndUserID(id, userID)
+func (uc *alamatUsecase) UpdateAlamat(ctx context.Context, id uint, req *domain.UpdateAlamatRequest, userID uint) (*domain.Alamat, error) {	
+	ctx, span := tracing.Start(ctx, "alamatUsecase.UpdateAlamat")
+	defer span.End()
+
+	alamat, err := uc.findOwnAlamat(ctx, id, userID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("alamat tidak ditemukan")
-		}
 		return nil, err
 	}
 
@@ -95,7 +89,7 @@ func (uc *alamatUsecase) UpdateAlamat(id uint, req *domain.UpdateAlamatRequest,
 		alamat.DetailAlamat = *req.DetailAlamat
 	}
 
-	err = uc.alamatRepo.Update(alamat)
+	err = uc.alamatRepo.Update(ctx, alamat)
 	if err != nil {
 		return nil, err
 	}
@@ -103,14 +97,32 @@ func (uc *alamatUsecase) UpdateAlamat(id uint, req *domain.UpdateAlamatRequest,
 	return alamat, nil
 }
 
-func (uc *alamatUsecase) DeleteAlamat(id uint, userID uint) error {	
-	_, err := uc.alamatRepo.FindByIDAndUserID(id, userID)
+func (uc *alamatUsecase) DeleteAlamat(ctx context.Context, id uint, userID uint) error {	
+	ctx, span := tracing.Start(ctx, "alamatUsecase.DeleteAlamat")
+	defer span.End()
+
+	if _, err := uc.findOwnAlamat(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return uc.alamatRepo.Delete(ctx, id, userID)
+}
+
+func (uc *alamatUsecase) findOwnAlamat(ctx context.Context, id uint, userID uint) (*domain.Alamat, error) {
+	alamat, err := uc.alamatRepo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("alamat tidak ditemukan")
+			return nil, domain.NewNotFoundError("alamat.not_found")
 		}
-		return err
+		return nil, err
 	}
 
-	return uc.alamatRepo.Delete(id, userID)
+	actor, err := uc.actors.Actor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.CanManageAlamat(actor, alamat) {
+		return nil, domain.NewNotFoundError("alamat.not_found")
+	}
+	return alamat, nil
 }
\ No newline at end of file
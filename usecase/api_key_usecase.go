@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"gogroceries/domain"
+	"gogroceries/internal/helper"
+	"gogroceries/internal/tracing"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const apiKeyLastUsedResolution = time.Minute
+
+var errInvalidApiKey = domain.NewUnauthorizedError("auth.api_key_invalid")
+
+type apiKeyUsecase struct {
+	apiKeyRepo domain.ApiKeyRepository
+	actors     domain.ActorProvider
+}
+
+func NewApiKeyUsecase(akr domain.ApiKeyRepository, ap domain.ActorProvider) domain.ApiKeyUsecase {
+	return &apiKeyUsecase{
+		apiKeyRepo: akr,
+		actors:     ap,
+	}
+}
+
+// myToko resolves the toko whose keys userID manages. Without tokoID the
+// user must manage the keys of exactly one toko.
+func (uc *apiKeyUsecase) myToko(ctx context.Context, tokoID, userID uint) (uint, *domain.Actor, error) {
+	actor, err := uc.actors.Actor(ctx, userID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if tokoID != 0 {
+		if !domain.CanManageApiKeys(actor, tokoID) {
+			return 0, nil, domain.NewForbiddenError("api_key.forbidden")
+		}
+		return tokoID, actor, nil
+	}
+
+	candidates := actor.TokoIDsWith(domain.TokoPermissionApiKeysManage)
+	switch len(candidates) {
+	case 1:
+		return candidates[0], actor, nil
+	case 0:
+		return 0, nil, domain.NewNotFoundError("toko.not_found_for_user")
+	default:
+		return 0, nil, domain.NewValidationError("api_key.toko_required", nil)
+	}
+}
+
+func (uc *apiKeyUsecase) GetMyApiKeys(ctx context.Context, tokoID, userID uint) ([]domain.ApiKey, error) {
+	ctx, span := tracing.Start(ctx, "apiKeyUsecase.GetMyApiKeys")
+	defer span.End()
+
+	tokoID, _, err := uc.myToko(ctx, tokoID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.apiKeyRepo.FindAllByTokoID(ctx, tokoID)
+}
+
+func (uc *apiKeyUsecase) ListMyApiKeys(ctx context.Context, tokoID, userID uint, page domain.PageRequest) (*domain.Page[domain.ApiKey], error) {
+	ctx, span := tracing.Start(ctx, "apiKeyUsecase.ListMyApiKeys")
+	defer span.End()
+
+	tokoID, _, err := uc.myToko(ctx, tokoID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	page = normalizePage(page)
+	keys, info, err := uc.apiKeyRepo.FindAllByTokoIDPaged(ctx, tokoID, page)
+	if err != nil {
+		return nil, err
+	}
+	return newPage(page, info, keys), nil
+}
+
+func (uc *apiKeyUsecase) CreateApiKey(ctx context.Context, req *domain.CreateApiKeyRequest, userID uint) (*domain.CreateApiKeyResponse, error) {
+	ctx, span := tracing.Start(ctx, "apiKeyUsecase.CreateApiKey")
+	defer span.End()
+
+	tokoID, actor, err := uc.myToko(ctx, req.IdToko, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var scopes []domain.TokoPermission
+	seen := map[domain.TokoPermission]bool{}
+	for _, scope := range req.Scopes {
+		if !domain.IsValidApiKeyScope(scope) {
+			return nil, domain.NewValidationError("api_key.scope_invalid", nil, "scope", scope)
+		}
+		if !actor.Membership(tokoID).Can(scope) {
+			return nil, domain.NewForbiddenError("api_key.scope_forbidden", "scope", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	prefix, err := helper.RandomToken(6)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat api key: %w", err)
+	}
+	prefix = strings.NewReplacer("-", "0", "_", "1").Replace(prefix)
+	secret, err := helper.RandomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat api key: %w", err)
+	}
+	rawKey := fmt.Sprintf("%s_%s_%s", domain.ApiKeyPrefix, prefix, secret)
+
+	apiKey := &domain.ApiKey{
+		IdToko:  tokoID,
+		IdUser:  userID,
+		Nama:    strings.TrimSpace(req.Nama),
+		Prefix:  prefix,
+		KeyHash: helper.HashToken(rawKey),
+		Scopes:  scopes,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	if err := uc.apiKeyRepo.Create(ctx, apiKey); err != nil {
+		return nil, fmt.Errorf("gagal menyimpan api key: %w", err)
+	}
+
+	return &domain.CreateApiKeyResponse{ApiKey: apiKey, Key: rawKey}, nil
+}
+
+func (uc *apiKeyUsecase) RevokeApiKey(ctx context.Context, id, userID uint) error {
+	ctx, span := tracing.Start(ctx, "apiKeyUsecase.RevokeApiKey")
+	defer span.End()
+
+	apiKey, err := uc.apiKeyRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.NewNotFoundError("api_key.not_found")
+		}
+		return err
+	}
+
+	actor, err := uc.actors.Actor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !domain.CanManageApiKeys(actor, apiKey.IdToko) {
+		return domain.NewNotFoundError("api_key.not_found")
+	}
+	if apiKey.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	apiKey.RevokedAt = &now
+	return uc.apiKeyRepo.Update(ctx, apiKey)
+}
+
+func (uc *apiKeyUsecase) Authenticate(ctx context.Context, rawKey string) (*domain.ApiKey, error) {
+	ctx, span := tracing.Start(ctx, "apiKeyUsecase.Authenticate")
+	defer span.End()
+
+	parts := strings.SplitN(rawKey, "_", 3)
+	if len(parts) != 3 || parts[0] != domain.ApiKeyPrefix || parts[1] == "" || parts[2] == "" {
+		return nil, errInvalidApiKey
+	}
+
+	apiKey, err := uc.apiKeyRepo.FindByPrefix(ctx, parts[1])
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errInvalidApiKey
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(helper.HashToken(rawKey))) != 1 {
+		return nil, errInvalidApiKey
+	}
+
+	now := time.Now()
+	if !apiKey.IsActive(now) {
+		return nil, domain.NewUnauthorizedError("api_key.inactive")
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyLastUsedResolution {
+		if err := uc.apiKeyRepo.TouchLastUsed(ctx, apiKey.ID, now); err == nil {
+			apiKey.LastUsedAt = &now
+		}
+	}
+
+	return apiKey, nil
+}
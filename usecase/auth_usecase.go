@@ -1,11 +1,12 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"gogroceries/domain"
 	"gogroceries/internal/helper"
-	"log"
+	"gogroceries/internal/tracing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -13,34 +14,43 @@ import (
 )
 
 type authUsecase struct {
-	userRepo domain.UserRepository
-	tokoRepo domain.TokoRepository
-	jwtAuth  helper.JWTInterface
+	userRepo     domain.UserRepository
+	tokoRepo     domain.TokoRepository
+	userRoleRepo domain.UserRoleRepository
+	memberRepo   domain.TokoMemberRepository
+	jwtAuth      helper.JWTInterface
+	txManager    domain.TxManager
 }
 
-func NewAuthUsecase(ur domain.UserRepository, tr domain.TokoRepository, jwtAuth helper.JWTInterface) domain.AuthUsecase {
+func NewAuthUsecase(ur domain.UserRepository, tr domain.TokoRepository, urr domain.UserRoleRepository, tmr domain.TokoMemberRepository, jwtAuth helper.JWTInterface, txm domain.TxManager) domain.AuthUsecase {
 	return &authUsecase{
-		userRepo: ur,
-		tokoRepo: tr,
-		jwtAuth:  jwtAuth,
+		userRepo:     ur,
+		tokoRepo:     tr,
+		userRoleRepo: urr,
+		memberRepo:   tmr,
+		jwtAuth:      jwtAuth,
+		txManager:    txm,
 	}
 }	
 
-func (uc *authUsecase) Register(req *domain.RegisterRequest) (*domain.User, error) {
-	existingUser, err := uc.userRepo.FindByEmail(req.Email)
+func (uc *authUsecase) Register(ctx context.Context, req *domain.RegisterRequest) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "authUsecase.Register")
+	defer span.End()
+
+	existingUser, err := uc.userRepo.FindByEmail(ctx, req.Email)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, errors.New("failed to check email")
 	}
 	if existingUser != nil {
-		return nil, errors.New("email already registered")
+		return nil, domain.NewConflictError("user.email_taken")
 	}
 
-	existingUser, err = uc.userRepo.FindByNoTelp(req.NoTelp)
+	existingUser, err = uc.userRepo.FindByNoTelp(ctx, req.NoTelp)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, errors.New("failed to check phone number")
 	}
 	if existingUser != nil {
-		return nil, errors.New("phone number already registered")
+		return nil, domain.NewConflictError("user.phone_taken")
 	}
 
 	hashedPassword, err := helper.HashPassword(req.KataSandi)
@@ -62,55 +72,92 @@ func (uc *authUsecase) Register(req *domain.RegisterRequest) (*domain.User, erro
 		IsAdmin:      false,
 	}
 
-	err = uc.userRepo.Create(newUser)
-	if err != nil {
-		return nil, errors.New("failed to create user")
-	}
-
-	newToko := &domain.Toko{
-		IdUser:   newUser.ID,
-		NamaToko: fmt.Sprintf("%s Toko", newUser.Nama),
-		UrlFoto:  "",
-	}
-	err = uc.tokoRepo.Create(newToko)
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.Create(ctx, newUser); err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		return provisionSellerToko(ctx, uc.tokoRepo, uc.memberRepo, uc.userRoleRepo, newUser)
+	})
 	if err != nil {
-		log.Printf("Warning: failed to create toko for user %d: %v", newUser.ID, err)
+		return nil, err
 	}
 
 	newUser.KataSandi = ""
 	return newUser, nil
 }
 
-func (uc *authUsecase) Login(req *domain.LoginRequest) (string, *domain.User, error) {
-	user, err := uc.userRepo.FindByNoTelp(req.NoTelp)
+func (uc *authUsecase) Login(ctx context.Context, req *domain.LoginRequest) (string, *domain.User, error) {
+	ctx, span := tracing.Start(ctx, "authUsecase.Login")
+	defer span.End()
+
+	user, err := uc.userRepo.FindByNoTelp(ctx, req.NoTelp)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", nil, errors.New("phone number or password is incorrect")
+			return "", nil, domain.NewUnauthorizedError("auth.invalid_credentials")
 		}
 		return "", nil, errors.New("failed to find user")
 	}
 
 	isValid := helper.CheckPasswordHash(req.KataSandi, user.KataSandi)
 	if !isValid {
-		return "", nil, errors.New("phone number or password is incorrect")
+		return "", nil, domain.NewUnauthorizedError("auth.invalid_credentials")
+	}
+
+	if user.IsSuspended() {
+		return "", nil, domain.NewForbiddenError("auth.suspended")
+	}
+
+	token, err := issueUserToken(ctx, uc.jwtAuth, uc.userRoleRepo, user)
+	if err != nil {
+		return "", nil, err
+	}
+
+	user.KataSandi = ""
+	return token, user, nil
+}
+
+// provisionSellerToko gives a freshly registered user their own toko, owner
+// membership and seller role. Call it in the same transaction that creates the
+// user so a failure never leaves an account without its shop.
+func provisionSellerToko(ctx context.Context, tokoRepo domain.TokoRepository, memberRepo domain.TokoMemberRepository, userRoleRepo domain.UserRoleRepository, user *domain.User) error {
+	newToko := &domain.Toko{
+		IdUser:   user.ID,
+		NamaToko: fmt.Sprintf("%s Toko", user.Nama),
+		UrlFoto:  "",
+	}
+	if err := tokoRepo.Create(ctx, newToko); err != nil {
+		return fmt.Errorf("failed to create toko: %w", err)
+	}
+	if err := memberRepo.Create(ctx, &domain.TokoMember{IdToko: newToko.ID, IdUser: user.ID, Role: domain.TokoRoleOwner}); err != nil {
+		return fmt.Errorf("failed to create owner membership: %w", err)
+	}
+	if err := userRoleRepo.Create(ctx, &domain.UserRole{IdUser: user.ID, Role: domain.RoleSeller}); err != nil {
+		return fmt.Errorf("failed to grant seller role: %w", err)
+	}
+	return nil
+}
+
+func issueUserToken(ctx context.Context, jwtAuth helper.JWTInterface, userRoleRepo domain.UserRoleRepository, user *domain.User) (string, error) {
+	roles, err := loadUserRoles(ctx, userRoleRepo, user)
+	if err != nil {
+		return "", errors.New("failed to load user roles")
 	}
 
 	claims := &domain.JWTClaims{
 		UserID:  user.ID,
 		Email:   user.Email,
 		IsAdmin: user.IsAdmin,
+		Roles:   roles,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)), 
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(jwtAuth.AccessTTL())),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token, err := uc.jwtAuth.GenerateToken(claims)
+	token, err := jwtAuth.GenerateToken(claims)
 	if err != nil {
-		return "", nil, errors.New("failed to generate token")
+		return "", errors.New("failed to generate token")
 	}
-
-	user.KataSandi = ""
-	return token, user, nil
-}
\ No newline at end of file
+	return token, nil
+}
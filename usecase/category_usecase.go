@@ -1,72 +1,409 @@
 package usecase
 
 import (
+	"context"
 	"errors"
-	"gogroceries/domain" 
+	"fmt"
+	"gogroceries/domain"
+	"gogroceries/internal/helper"
+	"gogroceries/internal/tracing"
+	"net/url"
 
 	"gorm.io/gorm"
 )
 
 type categoryUsecase struct {
 	categoryRepo domain.CategoryRepository
+	txManager    domain.TxManager
 }
 
-func NewCategoryUsecase(cr domain.CategoryRepository) domain.CategoryUsecase {
+func NewCategoryUsecase(cr domain.CategoryRepository, txm domain.TxManager) domain.CategoryUsecase {
 	return &categoryUsecase{
 		categoryRepo: cr,
+		txManager:    txm,
 	}
 }
 
-func (uc *categoryUsecase) CreateCategory(req *domain.CreateCategoryRequest) (*domain.Category, error) {
-	newCategory := &domain.Category{
-		NamaCategory: req.NamaCategory,
-	}
-	err := uc.categoryRepo.Create(newCategory)
+func (uc *categoryUsecase) CreateCategory(ctx context.Context, req *domain.CreateCategoryRequest) (*domain.Category, error) {
+	ctx, span := tracing.Start(ctx, "categoryUsecase.CreateCategory")
+	defer span.End()
+
+	var newCategory *domain.Category
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.checkParent(ctx, 0, req.ParentID); err != nil {
+			return err
+		}
+
+		slug, err := uc.resolveSlug(ctx, 0, req.Slug, req.NamaCategory)
+		if err != nil {
+			return err
+		}
+
+		newCategory = &domain.Category{
+			ParentID:     req.ParentID,
+			NamaCategory: req.NamaCategory,
+			Slug:         slug,
+			SortOrder:    req.SortOrder,
+			IconURL:      req.IconURL,
+		}
+		return uc.categoryRepo.Create(ctx, newCategory)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return newCategory, nil
 }
 
-func (uc *categoryUsecase) GetAllCategories() ([]domain.Category, error) {
-	return uc.categoryRepo.FindAll()
+func (uc *categoryUsecase) GetAllCategories(ctx context.Context) ([]domain.Category, error) {
+	ctx, span := tracing.Start(ctx, "categoryUsecase.GetAllCategories")
+	defer span.End()
+
+	return uc.categoryRepo.FindAll(ctx)
+}
+
+func (uc *categoryUsecase) ListCategories(ctx context.Context, page domain.PageRequest) (*domain.Page[domain.Category], error) {
+	ctx, span := tracing.Start(ctx, "categoryUsecase.ListCategories")
+	defer span.End()
+
+	page = normalizePage(page)
+	categories, info, err := uc.categoryRepo.FindAllPaged(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(page, info, categories), nil
 }
 
-func (uc *categoryUsecase) GetCategoryByID(id uint) (*domain.Category, error) {
-	category, err := uc.categoryRepo.FindByID(id)
+func (uc *categoryUsecase) GetCategoryTree(ctx context.Context) ([]domain.Category, error) {
+	ctx, span := tracing.Start(ctx, "categoryUsecase.GetCategoryTree")
+	defer span.End()
+
+	categories, err := uc.categoryRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return buildCategoryTree(categories), nil
+}
+
+// buildCategoryTree nests categories under their parents, keeping the order
+// they came in. Categories whose parent is missing are shown at the top level.
+func buildCategoryTree(categories []domain.Category) []domain.Category {
+	known := make(map[uint]bool, len(categories))
+	for _, category := range categories {
+		known[category.ID] = true
+	}
+
+	children := make(map[uint][]domain.Category)
+	for _, category := range categories {
+		var parentID uint
+		if category.ParentID != nil && known[*category.ParentID] {
+			parentID = *category.ParentID
+		}
+		children[parentID] = append(children[parentID], category)
+	}
+
+	var build func(parentID uint) []domain.Category
+	build = func(parentID uint) []domain.Category {
+		nodes := children[parentID]
+		for i := range nodes {
+			nodes[i].Children = build(nodes[i].ID)
+		}
+		return nodes
+	}
+
+	tree := build(0)
+	if tree == nil {
+		tree = []domain.Category{}
+	}
+	return tree
+}
+
+func (uc *categoryUsecase) GetCategoryByID(ctx context.Context, id uint) (*domain.Category, error) {
+	ctx, span := tracing.Start(ctx, "categoryUsecase.GetCategoryByID")
+	defer span.End()
+
+	return uc.findCategory(ctx, id)
+}
+
+func (uc *categoryUsecase) GetCategoryBySlug(ctx context.Context, slug string) (*domain.Category, error) {
+	ctx, span := tracing.Start(ctx, "categoryUsecase.GetCategoryBySlug")
+	defer span.End()
+
+	category, err := uc.categoryRepo.FindBySlug(ctx, slug)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("category tidak ditemukan")
+			return nil, domain.NewNotFoundError("category.not_found")
 		}
 		return nil, err
 	}
 	return category, nil
 }
 
-func (uc *categoryUsecase) UpdateCategory(id uint, req *domain.UpdateCategoryRequest) (*domain.Category, error) {
-	category, err := uc.categoryRepo.FindByID(id)
+func (uc *categoryUsecase) UpdateCategory(ctx context.Context, id uint, req *domain.UpdateCategoryRequest) (*domain.Category, error) {
+	ctx, span := tracing.Start(ctx, "categoryUsecase.UpdateCategory")
+	defer span.End()
+
+	category, err := uc.findCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DetachParent && req.ParentID != nil {
+		return nil, domain.NewValidationError("category.parent_invalid", map[string]string{
+			"detach_parent": "category.detach_conflict",
+		})
+	}
+	// The create request checks icon_url with the url binding; here an empty
+	// string has to pass because it removes the icon.
+	if req.IconURL != nil && *req.IconURL != "" && !isWebURL(*req.IconURL) {
+		return nil, domain.NewValidationError("category.icon_url_invalid", map[string]string{
+			"icon_url": "category.icon_url_format",
+		})
+	}
+
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.checkParent(ctx, id, req.ParentID); err != nil {
+			return err
+		}
+
+		if req.NamaCategory != nil {
+			category.NamaCategory = *req.NamaCategory
+		}
+		if req.Slug != "" && req.Slug != category.Slug {
+			slug, err := uc.resolveSlug(ctx, id, req.Slug, category.NamaCategory)
+			if err != nil {
+				return err
+			}
+			category.Slug = slug
+		}
+
+		if req.ParentID != nil {
+			category.ParentID = req.ParentID
+		} else if req.DetachParent {
+			category.ParentID = nil
+		}
+		if req.SortOrder != nil {
+			category.SortOrder = *req.SortOrder
+		}
+		if req.IconURL != nil {
+			category.IconURL = *req.IconURL
+		}
+		return uc.categoryRepo.Update(ctx, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (uc *categoryUsecase) GetCategoryUsage(ctx context.Context, id uint) (*domain.CategoryUsage, error) {
+	ctx, span := tracing.Start(ctx, "categoryUsecase.GetCategoryUsage")
+	defer span.End()
+
+	if _, err := uc.findCategory(ctx, id); err != nil {
+		return nil, err
+	}
+	return uc.usage(ctx, id)
+}
+
+func (uc *categoryUsecase) usage(ctx context.Context, id uint) (*domain.CategoryUsage, error) {
+	produks, err := uc.categoryRepo.CountProduk(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	children, err := uc.categoryRepo.CountChildren(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.CategoryUsage{Produk: produks, Children: children}, nil
+}
+
+// DeleteCategory only deletes a category nothing references any more, so
+// products never end up in a hidden category. Used categories are merged.
+// Products and sub-categories lock the row before they reference it, so the
+// counts hold until the delete commits.
+func (uc *categoryUsecase) DeleteCategory(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "categoryUsecase.DeleteCategory")
+	defer span.End()
+
+	return uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := uc.lockCategory(ctx, id); err != nil {
+			return err
+		}
+
+		usage, err := uc.usage(ctx, id)
+		if err != nil {
+			return err
+		}
+		if usage.Produk > 0 || usage.Children > 0 {
+			fields := map[string]string{}
+			if usage.Produk > 0 {
+				fields["produk"] = "category.in_use_produk"
+			}
+			if usage.Children > 0 {
+				fields["children"] = "category.in_use_children"
+			}
+			return &domain.Error{
+				Code:    domain.CodeConflict,
+				Message: "category.in_use",
+				Params:  map[string]interface{}{"produk": usage.Produk, "children": usage.Children},
+				Fields:  fields,
+			}
+		}
+		return uc.categoryRepo.Delete(ctx, id)
+	})
+}
+
+// MergeCategory moves the products and sub-categories of category id to the
+// target and deletes it, all in one transaction.
+func (uc *categoryUsecase) MergeCategory(ctx context.Context, id uint, req *domain.MergeCategoryRequest) (*domain.MergeCategoryResult, error) {
+	ctx, span := tracing.Start(ctx, "categoryUsecase.MergeCategory")
+	defer span.End()
+
+	if _, err := uc.findCategory(ctx, id); err != nil {
+		return nil, err
+	}
+
+	_, err := uc.categoryRepo.FindByID(ctx, req.TargetID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("category tidak ditemukan")
+			return nil, domain.NewValidationError("category.target_not_found", map[string]string{
+				"target_id": "category.id_not_found",
+			}, "id", req.TargetID)
+		}
+		return nil, fmt.Errorf("gagal validasi category tujuan: %w", err)
+	}
+
+	// The sub-categories move under the target, so it cannot be one of them.
+	subtree, err := uc.categoryRepo.SubtreeIDs(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("gagal validasi category tujuan: %w", err)
+	}
+	for _, descendantID := range subtree {
+		if descendantID == req.TargetID {
+			return nil, domain.NewValidationError("category.target_invalid", map[string]string{
+				"target_id": "category.target_in_subtree",
+			})
+		}
+	}
+
+	result := &domain.MergeCategoryResult{}
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Lock in id order so two merges between the same categories cannot deadlock.
+		first, second := id, req.TargetID
+		if first > second {
+			first, second = second, first
+		}
+		for _, lockID := range []uint{first, second} {
+			if _, err := uc.lockCategory(ctx, lockID); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if result.MovedChildren, err = uc.categoryRepo.ReassignChildren(ctx, id, req.TargetID); err != nil {
+			return err
+		}
+		if result.MovedProduk, err = uc.categoryRepo.ReassignProduk(ctx, id, req.TargetID); err != nil {
+			return err
+		}
+		return uc.categoryRepo.Delete(ctx, id)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gagal menggabungkan category: %w", err)
+	}
+
+	if result.Target, err = uc.findCategory(ctx, req.TargetID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (uc *categoryUsecase) findCategory(ctx context.Context, id uint) (*domain.Category, error) {
+	category, err := uc.categoryRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NewNotFoundError("category.not_found")
 		}
 		return nil, err
 	}
+	return category, nil
+}
 
-	category.NamaCategory = req.NamaCategory
-	err = uc.categoryRepo.Update(category)
+func (uc *categoryUsecase) lockCategory(ctx context.Context, id uint) (*domain.Category, error) {
+	category, err := uc.categoryRepo.LockByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NewNotFoundError("category.not_found")
+		}
 		return nil, err
 	}
 	return category, nil
 }
 
-func (uc *categoryUsecase) DeleteCategory(id uint) error {
-	_, err := uc.categoryRepo.FindByID(id)
+// checkParent validates the parent of category id (0 for a new category): it
+// must exist and must not be the category itself or one of its descendants.
+// The parent stays locked until the transaction ends, see DeleteCategory.
+func (uc *categoryUsecase) checkParent(ctx context.Context, id uint, parentID *uint) error {
+	if parentID == nil {
+		return nil
+	}
+
+	_, err := uc.categoryRepo.LockByID(ctx, *parentID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("category tidak ditemukan")
+			return domain.NewValidationError("category.parent_not_found", map[string]string{
+				"parent_id": "category.id_not_found",
+			}, "id", *parentID)
 		}
-		return err
+		return fmt.Errorf("gagal validasi parent category: %w", err)
 	}
-	return uc.categoryRepo.Delete(id)
-}
\ No newline at end of file
+
+	if id == 0 {
+		return nil
+	}
+	subtree, err := uc.categoryRepo.SubtreeIDs(ctx, id)
+	if err != nil {
+		return fmt.Errorf("gagal validasi parent category: %w", err)
+	}
+	for _, descendantID := range subtree {
+		if descendantID == *parentID {
+			return domain.NewValidationError("category.parent_invalid", map[string]string{
+				"parent_id": "category.parent_in_subtree",
+			})
+		}
+	}
+	return nil
+}
+
+// resolveSlug returns the slug for category id (0 for a new category): the
+// requested one, which must already be in slug form, or one generated from
+// nama. Either way it must not be taken by another category.
+func (uc *categoryUsecase) resolveSlug(ctx context.Context, id uint, slug, nama string) (string, error) {
+	if slug == "" {
+		slug = helper.Slugify(nama)
+		if slug == "" {
+			return "", domain.NewValidationError("category.slug_empty", map[string]string{
+				"slug": "category.slug_manual",
+			})
+		}
+	} else if slug != helper.Slugify(slug) {
+		return "", domain.NewValidationError("category.slug_invalid", map[string]string{
+			"slug": "category.slug_format",
+		})
+	}
+
+	existing, err := uc.categoryRepo.FindBySlug(ctx, slug)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", fmt.Errorf("gagal cek slug: %w", err)
+	}
+	if err == nil && existing.ID != id {
+		return "", domain.NewConflictError("category.slug_taken", "slug", slug)
+	}
+	return slug, nil
+}
+
+func isWebURL(raw string) bool {
+	u, err := url.ParseRequestURI(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"gogroceries/domain"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// memCategoryRepo keeps names and slugs unique among active categories only,
+// like the partial unique indexes on the categories table.
+type memCategoryRepo struct {
+	domain.CategoryRepository
+	categories []domain.Category
+}
+
+func (r *memCategoryRepo) active(id uint) *domain.Category {
+	for i := range r.categories {
+		if r.categories[i].ID == id && !r.categories[i].DeletedAt.Valid {
+			return &r.categories[i]
+		}
+	}
+	return nil
+}
+
+func (r *memCategoryRepo) FindByID(ctx context.Context, id uint) (*domain.Category, error) {
+	if category := r.active(id); category != nil {
+		found := *category
+		return &found, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memCategoryRepo) LockByID(ctx context.Context, id uint) (*domain.Category, error) {
+	return r.FindByID(ctx, id)
+}
+
+func (r *memCategoryRepo) FindBySlug(ctx context.Context, slug string) (*domain.Category, error) {
+	for _, category := range r.categories {
+		if category.Slug == slug && !category.DeletedAt.Valid {
+			return &category, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memCategoryRepo) CountChildren(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	for _, category := range r.categories {
+		if category.ParentID != nil && *category.ParentID == id && !category.DeletedAt.Valid {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memCategoryRepo) CountProduk(ctx context.Context, id uint) (int64, error) {
+	return 0, nil
+}
+
+func (r *memCategoryRepo) conflicts(category *domain.Category) bool {
+	for _, other := range r.categories {
+		if other.ID == category.ID || other.DeletedAt.Valid {
+			continue
+		}
+		sameParent := (other.ParentID == nil && category.ParentID == nil) ||
+			(other.ParentID != nil && category.ParentID != nil && *other.ParentID == *category.ParentID)
+		if other.Slug == category.Slug || (sameParent && other.NamaCategory == category.NamaCategory) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memCategoryRepo) Create(ctx context.Context, category *domain.Category) error {
+	if r.conflicts(category) {
+		return gorm.ErrDuplicatedKey
+	}
+	category.ID = uint(len(r.categories) + 1)
+	r.categories = append(r.categories, *category)
+	return nil
+}
+
+func (r *memCategoryRepo) Update(ctx context.Context, category *domain.Category) error {
+	if r.conflicts(category) {
+		return gorm.ErrDuplicatedKey
+	}
+	*r.active(category.ID) = *category
+	return nil
+}
+
+func (r *memCategoryRepo) Delete(ctx context.Context, id uint) error {
+	r.active(id).DeletedAt = gorm.DeletedAt{Valid: true}
+	return nil
+}
+
+func TestRecreateDeletedCategory(t *testing.T) {
+	ctx := context.Background()
+	repo := &memCategoryRepo{}
+	uc := NewCategoryUsecase(repo, noTx{})
+
+	sayur, err := uc.CreateCategory(ctx, &domain.CreateCategoryRequest{NamaCategory: "Sayur"})
+	if err != nil {
+		t.Fatalf("CreateCategory() error = %v", err)
+	}
+	if _, err := uc.CreateCategory(ctx, &domain.CreateCategoryRequest{NamaCategory: "Sayur"}); err == nil {
+		t.Fatal("CreateCategory() with an active duplicate name succeeded")
+	}
+
+	if err := uc.DeleteCategory(ctx, sayur.ID); err != nil {
+		t.Fatalf("DeleteCategory() error = %v", err)
+	}
+
+	recreated, err := uc.CreateCategory(ctx, &domain.CreateCategoryRequest{NamaCategory: "Sayur"})
+	if err != nil {
+		t.Fatalf("CreateCategory() after delete error = %v", err)
+	}
+	if recreated.ID == sayur.ID || recreated.Slug != "sayur" {
+		t.Errorf("recreated category = {ID: %d, Slug: %q}, want a new row with slug %q", recreated.ID, recreated.Slug, "sayur")
+	}
+}
+
+func TestUpdateCategoryKeepsOmittedName(t *testing.T) {
+	ctx := context.Background()
+	repo := &memCategoryRepo{}
+	uc := NewCategoryUsecase(repo, noTx{})
+
+	buah, err := uc.CreateCategory(ctx, &domain.CreateCategoryRequest{NamaCategory: "Buah"})
+	if err != nil {
+		t.Fatalf("CreateCategory() error = %v", err)
+	}
+
+	sortOrder := 3
+	updated, err := uc.UpdateCategory(ctx, buah.ID, &domain.UpdateCategoryRequest{SortOrder: &sortOrder})
+	if err != nil {
+		t.Fatalf("UpdateCategory() error = %v", err)
+	}
+	if updated.NamaCategory != "Buah" || updated.SortOrder != 3 {
+		t.Errorf("UpdateCategory() = {NamaCategory: %q, SortOrder: %d}, want {Buah, 3}", updated.NamaCategory, updated.SortOrder)
+	}
+
+	nama := "Buah Segar"
+	updated, err = uc.UpdateCategory(ctx, buah.ID, &domain.UpdateCategoryRequest{NamaCategory: &nama})
+	if err != nil {
+		t.Fatalf("UpdateCategory() error = %v", err)
+	}
+	if updated.NamaCategory != nama || updated.Slug != "buah" || updated.SortOrder != 3 {
+		t.Errorf("UpdateCategory() = {NamaCategory: %q, Slug: %q, SortOrder: %d}, want {%s, buah, 3}", updated.NamaCategory, updated.Slug, updated.SortOrder, nama)
+	}
+}
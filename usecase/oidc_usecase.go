@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gogroceries/domain"
+	"gogroceries/internal/helper"
+	"gogroceries/internal/tracing"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const oauthStateTTL = 10 * time.Minute
+
+type oidcUsecase struct {
+	providers    map[string]domain.OIDCProvider
+	stateRepo    domain.OAuthStateRepository
+	identityRepo domain.UserIdentityRepository
+	userRepo     domain.UserRepository
+	tokoRepo     domain.TokoRepository
+	userRoleRepo domain.UserRoleRepository
+	memberRepo   domain.TokoMemberRepository
+	jwtAuth      helper.JWTInterface
+	txManager    domain.TxManager
+}
+
+func NewOIDCUsecase(
+	providers map[string]domain.OIDCProvider,
+	osr domain.OAuthStateRepository,
+	uir domain.UserIdentityRepository,
+	ur domain.UserRepository,
+	tr domain.TokoRepository,
+	urr domain.UserRoleRepository,
+	tmr domain.TokoMemberRepository,
+	jwtAuth helper.JWTInterface,
+	txm domain.TxManager,
+) domain.OIDCUsecase {
+	return &oidcUsecase{
+		providers:    providers,
+		stateRepo:    osr,
+		identityRepo: uir,
+		userRepo:     ur,
+		tokoRepo:     tr,
+		userRoleRepo: urr,
+		memberRepo:   tmr,
+		jwtAuth:      jwtAuth,
+		txManager:    txm,
+	}
+}
+
+func (uc *oidcUsecase) provider(name string) (domain.OIDCProvider, error) {
+	p, ok := uc.providers[name]
+	if !ok {
+		return nil, domain.NewNotFoundError("oidc.provider_not_found", "provider", name)
+	}
+	return p, nil
+}
+
+func (uc *oidcUsecase) LoginURL(ctx context.Context, providerName string) (string, error) {
+	ctx, span := tracing.Start(ctx, "oidcUsecase.LoginURL")
+	defer span.End()
+
+	p, err := uc.provider(providerName)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := helper.RandomToken(32)
+	if err != nil {
+		return "", errors.New("failed to generate oauth state")
+	}
+	nonce, err := helper.RandomToken(32)
+	if err != nil {
+		return "", errors.New("failed to generate oauth nonce")
+	}
+	verifier, err := helper.RandomToken(32)
+	if err != nil {
+		return "", errors.New("failed to generate pkce verifier")
+	}
+
+	err = uc.stateRepo.Create(ctx, &domain.OAuthState{
+		State:        state,
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(oauthStateTTL),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to save oauth state: %w", err)
+	}
+
+	return p.AuthCodeURL(state, nonce, verifier), nil
+}
+
+func (uc *oidcUsecase) Callback(ctx context.Context, providerName, state, code string) (string, *domain.User, error) {
+	ctx, span := tracing.Start(ctx, "oidcUsecase.Callback")
+	defer span.End()
+
+	p, err := uc.provider(providerName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	saved, err := uc.stateRepo.Consume(ctx, state)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil, domain.NewUnauthorizedError("oidc.state_invalid")
+		}
+		return "", nil, err
+	}
+	if saved.Provider != providerName || time.Now().After(saved.ExpiresAt) {
+		return "", nil, domain.NewUnauthorizedError("oidc.state_invalid")
+	}
+
+	identity, err := p.Exchange(ctx, code, saved.CodeVerifier, saved.Nonce)
+	if err != nil {
+		return "", nil, &domain.Error{Code: domain.CodeUnauthorized, Message: "oidc.login_failed", Err: err}
+	}
+
+	user, err := uc.resolveUser(ctx, providerName, identity)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if user.IsSuspended() {
+		return "", nil, domain.NewForbiddenError("auth.suspended")
+	}
+
+	token, err := issueUserToken(ctx, uc.jwtAuth, uc.userRoleRepo, user)
+	if err != nil {
+		return "", nil, err
+	}
+
+	user.KataSandi = ""
+	return token, user, nil
+}
+
+// resolveUser finds the user behind an external identity. Unknown identities
+// are linked to an existing account only when the provider verified the email.
+func (uc *oidcUsecase) resolveUser(ctx context.Context, providerName string, identity *domain.OIDCIdentity) (*domain.User, error) {
+	linked, err := uc.identityRepo.FindByProviderSubject(ctx, providerName, identity.Subject)
+	if err == nil {
+		user, err := uc.userRepo.FindById(ctx, linked.IdUser)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, domain.NewNotFoundError("user.not_found")
+			}
+			return nil, err
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	email := strings.TrimSpace(identity.Email)
+	if email == "" || !identity.EmailVerified {
+		return nil, domain.NewUnauthorizedError("oidc.email_unverified")
+	}
+
+	user, err := uc.userRepo.FindByEmail(ctx, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("failed to check email")
+	}
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if user == nil {
+			if user, err = uc.createUser(ctx, providerName, identity, email); err != nil {
+				return err
+			}
+		}
+
+		err := uc.identityRepo.Create(ctx, &domain.UserIdentity{
+			IdUser:   user.ID,
+			Provider: providerName,
+			Subject:  identity.Subject,
+			Email:    email,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to link identity: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (uc *oidcUsecase) createUser(ctx context.Context, providerName string, identity *domain.OIDCIdentity, email string) (*domain.User, error) {
+	// Social accounts have no password or phone number; both columns are
+	// required and unique, so store an unusable hash and a per-identity placeholder.
+	secret, err := helper.RandomToken(32)
+	if err != nil {
+		return nil, errors.New("failed to generate password")
+	}
+	hashedPassword, err := helper.HashPassword(secret)
+	if err != nil {
+		return nil, errors.New("failed to hash password")
+	}
+
+	nama := strings.TrimSpace(identity.Name)
+	if nama == "" {
+		nama = strings.Split(email, "@")[0]
+	}
+
+	newUser := &domain.User{
+		Nama:       nama,
+		KataSandi:  hashedPassword,
+		NoTelp:     fmt.Sprintf("%s:%s", providerName, identity.Subject),
+		Email:      email,
+		NoPassword: true,
+	}
+	if err := uc.userRepo.Create(ctx, newUser); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	if err := provisionSellerToko(ctx, uc.tokoRepo, uc.memberRepo, uc.userRoleRepo, newUser); err != nil {
+		return nil, err
+	}
+	return newUser, nil
+}
+
+func (uc *oidcUsecase) CleanupExpiredStates(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "oidcUsecase.CleanupExpiredStates")
+	defer span.End()
+
+	return uc.stateRepo.DeleteExpired(ctx, time.Now())
+}
@@ -0,0 +1,318 @@
+package usecase
+
+import (
+	"context"
+	"gogroceries/config"
+	"gogroceries/domain"
+	"gogroceries/internal/helper"
+	"gogroceries/internal/oidc"
+	"gogroceries/internal/oidcmock"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type memStateRepo struct {
+	states map[string]domain.OAuthState
+}
+
+func (r *memStateRepo) Create(ctx context.Context, state *domain.OAuthState) error {
+	r.states[state.State] = *state
+	return nil
+}
+
+func (r *memStateRepo) Consume(ctx context.Context, state string) (*domain.OAuthState, error) {
+	saved, ok := r.states[state]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	delete(r.states, state)
+	return &saved, nil
+}
+
+func (r *memStateRepo) DeleteExpired(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+type memIdentityRepo struct {
+	identities []domain.UserIdentity
+}
+
+func (r *memIdentityRepo) Create(ctx context.Context, identity *domain.UserIdentity) error {
+	r.identities = append(r.identities, *identity)
+	return nil
+}
+
+func (r *memIdentityRepo) FindByProviderSubject(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	for i := range r.identities {
+		if r.identities[i].Provider == provider && r.identities[i].Subject == subject {
+			return &r.identities[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memIdentityRepo) FindAllByUserID(ctx context.Context, userID uint) ([]domain.UserIdentity, error) {
+	var found []domain.UserIdentity
+	for _, identity := range r.identities {
+		if identity.IdUser == userID {
+			found = append(found, identity)
+		}
+	}
+	return found, nil
+}
+
+// memUserRepo implements the UserRepository methods used by the OIDC flow;
+// calling any other method panics on the nil embedded interface.
+type memUserRepo struct {
+	domain.UserRepository
+	users map[uint]*domain.User
+}
+
+func (r *memUserRepo) Create(ctx context.Context, user *domain.User) error {
+	user.ID = uint(len(r.users) + 1)
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *memUserRepo) FindById(ctx context.Context, id uint) (*domain.User, error) {
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memUserRepo) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type memTokoRepo struct {
+	domain.TokoRepository
+	tokos []domain.Toko
+}
+
+func (r *memTokoRepo) Create(ctx context.Context, toko *domain.Toko) error {
+	toko.ID = uint(len(r.tokos) + 1)
+	r.tokos = append(r.tokos, *toko)
+	return nil
+}
+
+type memMemberRepo struct {
+	domain.TokoMemberRepository
+	members []domain.TokoMember
+}
+
+func (r *memMemberRepo) Create(ctx context.Context, member *domain.TokoMember) error {
+	r.members = append(r.members, *member)
+	return nil
+}
+
+type memUserRoleRepo struct {
+	roles []domain.UserRole
+}
+
+func (r *memUserRoleRepo) FindByUserID(ctx context.Context, userID uint) ([]domain.UserRole, error) {
+	var found []domain.UserRole
+	for _, role := range r.roles {
+		if role.IdUser == userID {
+			found = append(found, role)
+		}
+	}
+	return found, nil
+}
+
+func (r *memUserRoleRepo) Create(ctx context.Context, userRole *domain.UserRole) error {
+	r.roles = append(r.roles, *userRole)
+	return nil
+}
+
+func (r *memUserRoleRepo) Delete(ctx context.Context, userID uint, role domain.Role) error {
+	return nil
+}
+
+type noTx struct{}
+
+func (noTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type oidcFixture struct {
+	mock       *oidcmock.Server
+	uc         domain.OIDCUsecase
+	users      *memUserRepo
+	identities *memIdentityRepo
+	tokos      *memTokoRepo
+}
+
+func newOIDCFixture(t *testing.T) *oidcFixture {
+	t.Helper()
+
+	mock, err := oidcmock.Start("gogroceries", "mock-secret")
+	if err != nil {
+		t.Fatalf("start oidcmock: %v", err)
+	}
+	t.Cleanup(mock.Close)
+
+	provider, err := oidc.NewProvider(config.OIDCProviderConfig{
+		Name:         "mock",
+		Issuer:       mock.Issuer,
+		ClientID:     "gogroceries",
+		ClientSecret: "mock-secret",
+		RedirectURL:  "http://localhost:8080/api/v1/auth/oidc/mock/callback",
+	})
+	if err != nil {
+		t.Fatalf("discover oidcmock: %v", err)
+	}
+
+	jwtAuth, err := helper.NewJWTHelper(config.Config{AppEnv: "test", JWTSecret: "secret", JWTAlgorithm: "HS256"})
+	if err != nil {
+		t.Fatalf("jwt helper: %v", err)
+	}
+
+	f := &oidcFixture{
+		mock:       mock,
+		users:      &memUserRepo{users: map[uint]*domain.User{}},
+		identities: &memIdentityRepo{},
+		tokos:      &memTokoRepo{},
+	}
+	f.uc = NewOIDCUsecase(
+		map[string]domain.OIDCProvider{"mock": provider},
+		&memStateRepo{states: map[string]domain.OAuthState{}},
+		f.identities,
+		f.users,
+		f.tokos,
+		&memUserRoleRepo{},
+		&memMemberRepo{},
+		jwtAuth,
+		noTx{},
+	)
+	return f
+}
+
+// login follows the authorization redirect the way a browser would and
+// returns the state and code the provider sends back to the callback.
+func (f *oidcFixture) login(t *testing.T) (state, code string) {
+	t.Helper()
+
+	loginURL, err := f.uc.LoginURL(context.Background(), "mock")
+	if err != nil {
+		t.Fatalf("LoginURL: %v", err)
+	}
+	authURL, _ := url.Parse(loginURL)
+	if authURL.Query().Get("code_challenge_method") != "S256" || authURL.Query().Get("code_challenge") == "" {
+		t.Fatalf("login url has no S256 PKCE challenge: %s", loginURL)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(loginURL)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	resp.Body.Close()
+
+	callback, err := resp.Location()
+	if err != nil {
+		t.Fatalf("authorize did not redirect: %v", err)
+	}
+	if e := callback.Query().Get("error"); e != "" {
+		t.Fatalf("authorize returned %s", e)
+	}
+	return callback.Query().Get("state"), callback.Query().Get("code")
+}
+
+func TestOIDCLoginCreatesUser(t *testing.T) {
+	f := newOIDCFixture(t)
+	f.mock.SetIdentity(oidcmock.Identity{Subject: "sub-1", Email: "baru@example.com", EmailVerified: true, Name: "Pengguna Baru"})
+
+	state, code := f.login(t)
+	token, user, err := f.uc.Callback(context.Background(), "mock", state, code)
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if token == "" {
+		t.Error("Callback returned an empty token")
+	}
+	if user.Email != "baru@example.com" || user.Nama != "Pengguna Baru" {
+		t.Errorf("created user = %q <%s>", user.Nama, user.Email)
+	}
+	if !user.NoPassword {
+		t.Error("user created through social login should be marked NoPassword")
+	}
+	if len(f.tokos.tokos) != 1 {
+		t.Errorf("new user got %d tokos, want 1", len(f.tokos.tokos))
+	}
+
+	state, code = f.login(t)
+	_, again, err := f.uc.Callback(context.Background(), "mock", state, code)
+	if err != nil {
+		t.Fatalf("second Callback: %v", err)
+	}
+	if again.ID != user.ID || len(f.users.users) != 1 {
+		t.Errorf("second login resolved user %d of %d, want the existing user %d", again.ID, len(f.users.users), user.ID)
+	}
+}
+
+func TestOIDCLoginLinksExistingAccountByEmail(t *testing.T) {
+	f := newOIDCFixture(t)
+	existing := &domain.User{Nama: "Udin", Email: "udin@example.com", NoTelp: "081200000001", KataSandi: "hash"}
+	f.users.Create(context.Background(), existing)
+	f.mock.SetIdentity(oidcmock.Identity{Subject: "sub-udin", Email: "udin@example.com", EmailVerified: true, Name: "Udin Google"})
+
+	state, code := f.login(t)
+	_, user, err := f.uc.Callback(context.Background(), "mock", state, code)
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if user.ID != existing.ID || len(f.users.users) != 1 {
+		t.Fatalf("logged in as user %d, want the existing user %d", user.ID, existing.ID)
+	}
+	linked, err := f.identities.FindByProviderSubject(context.Background(), "mock", "sub-udin")
+	if err != nil || linked.IdUser != existing.ID {
+		t.Errorf("identity not linked to the existing user: %+v, %v", linked, err)
+	}
+	if user.NoPassword {
+		t.Error("linking a provider must not waive the password of an existing account")
+	}
+	if len(f.tokos.tokos) != 0 {
+		t.Errorf("linking created %d tokos, want 0", len(f.tokos.tokos))
+	}
+}
+
+func TestOIDCLoginRejectsUnverifiedEmail(t *testing.T) {
+	f := newOIDCFixture(t)
+	existing := &domain.User{Nama: "Udin", Email: "udin@example.com", NoTelp: "081200000001", KataSandi: "hash"}
+	f.users.Create(context.Background(), existing)
+	f.mock.SetIdentity(oidcmock.Identity{Subject: "sub-evil", Email: "udin@example.com", EmailVerified: false})
+
+	state, code := f.login(t)
+	_, _, err := f.uc.Callback(context.Background(), "mock", state, code)
+	if domain.ErrorCodeOf(err) != domain.CodeUnauthorized {
+		t.Fatalf("Callback error = %v, want UNAUTHORIZED", err)
+	}
+	if len(f.identities.identities) != 0 {
+		t.Errorf("unverified email was linked")
+	}
+}
+
+func TestOIDCCallbackRejectsReusedState(t *testing.T) {
+	f := newOIDCFixture(t)
+
+	state, code := f.login(t)
+	if _, _, err := f.uc.Callback(context.Background(), "mock", state, code); err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	_, _, err := f.uc.Callback(context.Background(), "mock", state, code)
+	if domain.ErrorCodeOf(err) != domain.CodeUnauthorized {
+		t.Fatalf("reused state error = %v, want UNAUTHORIZED", err)
+	}
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"gogroceries/domain"
+	"math"
+)
+
+const defaultPageLimit = 10
+
+// normalizePage applies the default page size. Page numbers are only used
+// when no cursor is given. The maximum is a v2 rule and applied by the
+// handlers, so v1 keeps serving the limits it always accepted.
+func normalizePage(page domain.PageRequest) domain.PageRequest {
+	if page.Limit <= 0 {
+		page.Limit = defaultPageLimit
+	}
+	if page.Cursor != "" || page.Page < 1 {
+		page.Page = 1
+	}
+	return page
+}
+
+// newPage wraps the rows of one page with the cursors and, when it was
+// counted, the total. Page is only echoed for page-number requests.
+func newPage[T any](page domain.PageRequest, info *domain.PageInfo, items []T) *domain.Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	result := &domain.Page[T]{
+		Items:      items,
+		Limit:      page.Limit,
+		NextCursor: info.NextCursor,
+		PrevCursor: info.PrevCursor,
+	}
+	if page.Cursor == "" {
+		result.Page = page.Page
+	}
+	if info.Total != nil {
+		totalPage := int(math.Ceil(float64(*info.Total) / float64(page.Limit)))
+		result.TotalData = info.Total
+		result.TotalPage = &totalPage
+	}
+	return result
+}
@@ -1,12 +1,15 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"gogroceries/domain" 
 	"gogroceries/internal/helper" 
-	"log"
-	"math"
+	"gogroceries/internal/logger"
+	"gogroceries/internal/metrics"
+	"gogroceries/internal/tracing"
+	"strings"
 	"time"
 
 
@@ -17,46 +20,42 @@ type produkUsecase struct {
 	produkRepo   domain.ProdukRepository
 	tokoRepo    domain.TokoRepository
 	categoryRepo domain.CategoryRepository 
+	actors       domain.ActorProvider
+	txManager    domain.TxManager
 }
 
-func NewProdukUsecase(pr domain.ProdukRepository, tr domain.TokoRepository, cr domain.CategoryRepository) domain.ProdukUsecase {
+func NewProdukUsecase(pr domain.ProdukRepository, tr domain.TokoRepository, cr domain.CategoryRepository, ap domain.ActorProvider, txm domain.TxManager) domain.ProdukUsecase {
 	return &produkUsecase{
 		produkRepo:   pr,
 		tokoRepo:    tr,
 		categoryRepo: cr,
+		actors:       ap,
+		txManager:    txm,
 	}
 }
 
-func (uc *produkUsecase) CreateProduk(req *domain.CreateProdukRequest, userID uint) (*domain.Produk, error) {
-	toko, err := uc.tokoRepo.FindByUserID(userID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("toko tidak ditemukan untuk user ini, tidak bisa menambah produk")
-		}
-		return nil, fmt.Errorf("gagal mencari toko: %w", err)
-	}
+func (uc *produkUsecase) CreateProduk(ctx context.Context, req *domain.CreateProdukRequest, userID uint) (*domain.Produk, error) {
+	ctx, span := tracing.Start(ctx, "produkUsecase.CreateProduk")
+	defer span.End()
 
-	_, err = uc.categoryRepo.FindByID(req.IdCategory)
+	tokoID, err := uc.resolveTokoID(ctx, req.IdToko, userID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("category dengan ID %d tidak ditemukan", req.IdCategory)
-		}
-		return nil, fmt.Errorf("gagal validasi category: %w", err)
+		return nil, err
 	}
 
 	slug := helper.Slugify(req.NamaProduk)
-	_, err = uc.produkRepo.FindBySlug(slug)
+	_, err = uc.produkRepo.FindBySlug(ctx, slug)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, fmt.Errorf("gagal cek slug: %w", err)
 	}
 	if err == nil { 
 		slug = fmt.Sprintf("%s-%d", slug, time.Now().UnixNano())
-		log.Printf("Slug duplikat, generate slug baru: %s", slug)
+		logger.FromContext(ctx).Info("duplicate slug, generated a new one", "slug", slug)
 	}
 
 
 	newProduk := &domain.Produk{
-		IdToko:        toko.ID,
+		IdToko:        tokoID,
 		IdCategory:    req.IdCategory,
 		NamaProduk:    req.NamaProduk,
 		Slug:          slug,
@@ -66,75 +65,106 @@ func (uc *produkUsecase) CreateProduk(req *domain.CreateProdukRequest, userID ui
 		Deskripsi:     req.Deskripsi,
 	}
 
-	createdProduk, err := uc.produkRepo.Create(newProduk, req.Photos) 
+	// Locking the category keeps it from being deleted before the product
+	// that references it is committed.
+	var createdProduk *domain.Produk
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := uc.categoryRepo.LockByID(ctx, req.IdCategory)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.NewNotFoundError("category.id_not_found", "id", req.IdCategory)
+			}
+			return fmt.Errorf("gagal validasi category: %w", err)
+		}
+
+		createdProduk, err = uc.produkRepo.Create(ctx, newProduk, req.Photos)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
+	metrics.ProductChanges.WithLabelValues("created").Inc()
 
 	return createdProduk, nil
 }
 
-func (uc *produkUsecase) GetAllProduk(filter domain.ProdukFilter, page, limit int) ([]domain.Produk, *domain.PaginationResponse, error) {
-	if page <= 0 {
-		page = 1
-	}
-	if limit <= 0 {
-		limit = 10
+func (uc *produkUsecase) GetAllProduk(ctx context.Context, filter domain.ProdukFilter, page domain.PageRequest) (*domain.Page[domain.Produk], *domain.ProdukFacets, error) {
+	ctx, span := tracing.Start(ctx, "produkUsecase.GetAllProduk")
+	defer span.End()
+
+	if filter.Sort != "" && !filter.Sort.Valid() {
+		return nil, nil, domain.NewValidationError("produk.sort_invalid", map[string]string{
+			"sort": "produk.sort_options",
+		}, "sort", filter.Sort, "options", joinProdukSorts())
 	}
-	offset := (page - 1) * limit
 
-	produks, totalData, err := uc.produkRepo.FindAll(filter, limit, offset)
+	page = normalizePage(page)
+	produks, info, err := uc.produkRepo.FindAll(ctx, filter, page)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	totalPages := 0
-	if totalData > 0 {
-		totalPages = int(math.Ceil(float64(totalData) / float64(limit)))
+	facets, err := uc.produkRepo.Facets(ctx, filter)
+	if err != nil {
+		return nil, nil, err
 	}
 
+	return newPage(page, info, produks), facets, nil
+}
 
-	pagination := &domain.PaginationResponse{
-		Page:      page,
-		Limit:     limit,
-		TotalData: int(totalData),
-		TotalPage: totalPages,
-		Data:      produks,
+func joinProdukSorts() string {
+	sorts := make([]string, len(domain.ProdukSorts))
+	for i, sort := range domain.ProdukSorts {
+		sorts[i] = string(sort)
 	}
-
-	return produks, pagination, nil
+	return strings.Join(sorts, ", ")
 }
 
-func (uc *produkUsecase) GetProdukByID(id uint) (*domain.Produk, error) {
-	produk, err := uc.produkRepo.FindByID(id)
+func (uc *produkUsecase) GetProdukByID(ctx context.Context, id uint) (*domain.Produk, error) {
+	ctx, span := tracing.Start(ctx, "produkUsecase.GetProdukByID")
+	defer span.End()
+
+	produk, err := uc.produkRepo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("produk tidak ditemukan")
+			return nil, domain.NewNotFoundError("produk.not_found")
 		}
 		return nil, err
 	}
+	if produk == nil {
+		return nil, domain.NewNotFoundError("produk.not_found")
+	}
 	return produk, nil
 }
 
-func (uc *produkUsecase) UpdateProduk(id uint, req *domain.UpdateProdukRequest, userID uint) (*domain.Produk, error) {
-	produk, err := uc.produkRepo.FindByID(id)
+func (uc *produkUsecase) UpdateProduk(ctx context.Context, id uint, req *domain.UpdateProdukRequest, userID uint) (*domain.Produk, error) {
+	ctx, span := tracing.Start(ctx, "produkUsecase.UpdateProduk")
+	defer span.End()
+
+	produk, err := uc.produkRepo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("produk tidak ditemukan")
+			return nil, domain.NewNotFoundError("produk.not_found")
 		}
 		return nil, err
 	}
+	if produk == nil {
+		return nil, domain.NewNotFoundError("produk.not_found")
+	}
 
-	toko, err := uc.tokoRepo.FindByID(userID)
-	if err != nil || toko.ID != produk.IdToko {
-		return nil, errors.New("forbidden: anda tidak bisa mengubah produk ini")
+	actor, err := uc.actors.Actor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.CanEditProduk(actor, produk) {
+		return nil, domain.NewForbiddenError("produk.update_forbidden")
 	}
 
 	if req.NamaProduk != "" {
 		produk.NamaProduk = req.NamaProduk
 		newSlug := helper.Slugify(req.NamaProduk)
         if newSlug != produk.Slug {
-             _, slugErr := uc.produkRepo.FindBySlug(newSlug)
+             _, slugErr := uc.produkRepo.FindBySlug(ctx, newSlug)
              if slugErr != nil && !errors.Is(slugErr, gorm.ErrRecordNotFound){
                 return nil, fmt.Errorf("gagal cek slug baru: %w", slugErr)
              }
@@ -144,13 +174,6 @@ func (uc *produkUsecase) UpdateProduk(id uint, req *domain.UpdateProdukRequest,
              produk.Slug = newSlug
         }
 	}
-	if req.IdCategory != 0 {
-		_, catErr := uc.categoryRepo.FindByID(req.IdCategory)
-		if catErr != nil {
-			return nil, fmt.Errorf("category ID %d tidak valid", req.IdCategory)
-		}
-		produk.IdCategory = req.IdCategory
-	}
 	if req.HargaReseller > 0 { 
 		produk.HargaReseller = req.HargaReseller
 	}
@@ -165,27 +188,83 @@ func (uc *produkUsecase) UpdateProduk(id uint, req *domain.UpdateProdukRequest,
 	}
 
 
-	err = uc.produkRepo.Update(produk)
+	// Same category lock as CreateProduk.
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if req.IdCategory != 0 {
+			if _, err := uc.categoryRepo.LockByID(ctx, req.IdCategory); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return domain.NewValidationError("category.id_not_found", nil, "id", req.IdCategory)
+				}
+				return fmt.Errorf("gagal validasi category: %w", err)
+			}
+			produk.IdCategory = req.IdCategory
+		}
+		return uc.produkRepo.Update(ctx, produk)
+	})
 	if err != nil {
 		return nil, err
 	}
-	updatedProduk, _ := uc.produkRepo.FindByID(id) 
+	metrics.ProductChanges.WithLabelValues("updated").Inc()
+	updatedProduk, err := uc.produkRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat produk: %w", err)
+	}
+	if updatedProduk == nil {
+		return nil, domain.NewNotFoundError("produk.not_found")
+	}
 	return updatedProduk, nil
 }
 
-func (uc *produkUsecase) DeleteProduk(id uint, userID uint) error {
-	produk, err := uc.produkRepo.FindByID(id)
+func (uc *produkUsecase) DeleteProduk(ctx context.Context, id uint, userID uint) error {
+	ctx, span := tracing.Start(ctx, "produkUsecase.DeleteProduk")
+	defer span.End()
+
+	produk, err := uc.produkRepo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("produk tidak ditemukan")
+			return domain.NewNotFoundError("produk.not_found")
 		}
 		return err
 	}
+	if produk == nil {
+		return domain.NewNotFoundError("produk.not_found")
+	}
 
-	toko, err := uc.tokoRepo.FindByID(userID)
-	if err != nil || toko.ID != produk.IdToko {
-		return errors.New("forbidden: anda tidak bisa menghapus produk ini")
+	actor, err := uc.actors.Actor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !domain.CanEditProduk(actor, produk) {
+		return domain.NewForbiddenError("produk.delete_forbidden")
 	}
 
-	return uc.produkRepo.Delete(id)
-}
\ No newline at end of file
+	if err := uc.produkRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	metrics.ProductChanges.WithLabelValues("deleted").Inc()
+	return nil
+}
+
+func (uc *produkUsecase) resolveTokoID(ctx context.Context, tokoID uint, userID uint) (uint, error) {
+	actor, err := uc.actors.Actor(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if tokoID != 0 {
+		if !domain.CanCreateProdukInToko(actor, tokoID) {
+			return 0, domain.NewForbiddenError("produk.create_forbidden")
+		}
+		return tokoID, nil
+	}
+
+	candidates := actor.TokoIDsWith(domain.TokoPermissionProdukWrite)
+	switch len(candidates) {
+	case 1:
+		return candidates[0], nil
+	case 0:
+		return 0, domain.NewNotFoundError("produk.no_toko")
+	default:
+		return 0, domain.NewValidationError("produk.toko_id_required", nil)
+	}
+}
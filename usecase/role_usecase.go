@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gogroceries/domain"
+	"gogroceries/internal/tracing"
+
+	"gorm.io/gorm"
+)
+
+type roleUsecase struct {
+	userRepo     domain.UserRepository
+	userRoleRepo domain.UserRoleRepository
+}
+
+func NewRoleUsecase(ur domain.UserRepository, urr domain.UserRoleRepository) domain.RoleUsecase {
+	return &roleUsecase{
+		userRepo:     ur,
+		userRoleRepo: urr,
+	}
+}
+
+func (uc *roleUsecase) GetUserRoles(ctx context.Context, userID uint) ([]domain.Role, error) {
+	ctx, span := tracing.Start(ctx, "roleUsecase.GetUserRoles")
+	defer span.End()
+
+	user, err := uc.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.rolesOf(ctx, user)
+}
+
+func (uc *roleUsecase) GrantRole(ctx context.Context, userID uint, role domain.Role) ([]domain.Role, error) {
+	ctx, span := tracing.Start(ctx, "roleUsecase.GrantRole")
+	defer span.End()
+
+	if !domain.IsValidRole(role) {
+		return nil, domain.NewValidationError("role.invalid", nil, "role", role)
+	}
+
+	user, err := uc.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.userRoleRepo.Create(ctx, &domain.UserRole{IdUser: user.ID, Role: role}); err != nil {
+		return nil, fmt.Errorf("gagal menambah role: %w", err)
+	}
+
+	if role == domain.RoleAdmin && !user.IsAdmin {
+		user.IsAdmin = true
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return nil, fmt.Errorf("gagal update status admin: %w", err)
+		}
+	}
+
+	return uc.rolesOf(ctx, user)
+}
+
+func (uc *roleUsecase) RevokeRole(ctx context.Context, userID uint, role domain.Role) ([]domain.Role, error) {
+	ctx, span := tracing.Start(ctx, "roleUsecase.RevokeRole")
+	defer span.End()
+
+	if !domain.IsValidRole(role) {
+		return nil, domain.NewValidationError("role.invalid", nil, "role", role)
+	}
+
+	user, err := uc.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.userRoleRepo.Delete(ctx, user.ID, role); err != nil {
+		return nil, fmt.Errorf("gagal mencabut role: %w", err)
+	}
+
+	if role == domain.RoleAdmin && user.IsAdmin {
+		user.IsAdmin = false
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return nil, fmt.Errorf("gagal update status admin: %w", err)
+		}
+	}
+
+	return uc.rolesOf(ctx, user)
+}
+
+func (uc *roleUsecase) findUser(ctx context.Context, userID uint) (*domain.User, error) {
+	user, err := uc.userRepo.FindById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NewNotFoundError("user.not_found")
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (uc *roleUsecase) rolesOf(ctx context.Context, user *domain.User) ([]domain.Role, error) {
+	return loadUserRoles(ctx, uc.userRoleRepo, user)
+}
+
+func loadUserRoles(ctx context.Context, userRoleRepo domain.UserRoleRepository, user *domain.User) ([]domain.Role, error) {
+	userRoles, err := userRoleRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]domain.Role, 0, len(userRoles)+1)
+	hasAdmin := false
+	for _, ur := range userRoles {
+		if ur.Role == domain.RoleAdmin {
+			hasAdmin = true
+		}
+		roles = append(roles, ur.Role)
+	}
+	if user.IsAdmin && !hasAdmin {
+		roles = append(roles, domain.RoleAdmin)
+	}
+
+	return roles, nil
+}
@@ -0,0 +1,366 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gogroceries/domain"
+	"gogroceries/internal/tracing"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const tokoInvitationTTL = 7 * 24 * time.Hour
+
+type tokoMemberUsecase struct {
+	memberRepo     domain.TokoMemberRepository
+	invitationRepo domain.TokoInvitationRepository
+	userRepo       domain.UserRepository
+	actors         domain.ActorProvider
+	txManager      domain.TxManager
+}
+
+func NewTokoMemberUsecase(
+	tmr domain.TokoMemberRepository,
+	tir domain.TokoInvitationRepository,
+	ur domain.UserRepository,
+	ap domain.ActorProvider,
+	txm domain.TxManager,
+) domain.TokoMemberUsecase {
+	return &tokoMemberUsecase{
+		memberRepo:     tmr,
+		invitationRepo: tir,
+		userRepo:       ur,
+		actors:         ap,
+		txManager:      txm,
+	}
+}
+
+func (uc *tokoMemberUsecase) GetMyMemberships(ctx context.Context, userID uint) ([]domain.TokoMember, error) {
+	ctx, span := tracing.Start(ctx, "tokoMemberUsecase.GetMyMemberships")
+	defer span.End()
+
+	return uc.memberRepo.FindAllByUserID(ctx, userID)
+}
+
+func (uc *tokoMemberUsecase) ListMyMemberships(ctx context.Context, userID uint, page domain.PageRequest) (*domain.Page[domain.TokoMember], error) {
+	ctx, span := tracing.Start(ctx, "tokoMemberUsecase.ListMyMemberships")
+	defer span.End()
+
+	page = normalizePage(page)
+	members, info, err := uc.memberRepo.FindAllByUserIDPaged(ctx, userID, page)
+	if err != nil {
+		return nil, err
+	}
+	return newPage(page, info, members), nil
+}
+
+func (uc *tokoMemberUsecase) GetMembers(ctx context.Context, tokoID, userID uint) ([]domain.TokoMember, error) {
+	ctx, span := tracing.Start(ctx, "tokoMemberUsecase.GetMembers")
+	defer span.End()
+
+	actor, err := uc.actors.Actor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.CanViewTokoMembers(actor, tokoID) {
+		return nil, domain.NewForbiddenError("toko_member.not_member")
+	}
+	return uc.memberRepo.FindAllByTokoID(ctx, tokoID)
+}
+
+func (uc *tokoMemberUsecase) ListMembers(ctx context.Context, tokoID, userID uint, page domain.PageRequest) (*domain.Page[domain.TokoMember], error) {
+	ctx, span := tracing.Start(ctx, "tokoMemberUsecase.ListMembers")
+	defer span.End()
+
+	actor, err := uc.actors.Actor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.CanViewTokoMembers(actor, tokoID) {
+		return nil, domain.NewForbiddenError("toko_member.not_member")
+	}
+
+	page = normalizePage(page)
+	members, info, err := uc.memberRepo.FindAllByTokoIDPaged(ctx, tokoID, page)
+	if err != nil {
+		return nil, err
+	}
+	return newPage(page, info, members), nil
+}
+
+func (uc *tokoMemberUsecase) InviteMember(ctx context.Context, tokoID uint, req *domain.InviteTokoMemberRequest, userID uint) (*domain.TokoInvitation, error) {
+	ctx, span := tracing.Start(ctx, "tokoMemberUsecase.InviteMember")
+	defer span.End()
+
+	if !domain.IsValidTokoRole(req.Role) || req.Role == domain.TokoRoleOwner {
+		return nil, domain.NewValidationError("toko_member.role_invalid", nil, "role", req.Role)
+	}
+
+	actor, err := uc.actors.Actor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.CanAssignTokoRole(actor, tokoID, "", req.Role) {
+		return nil, domain.NewForbiddenError("toko_member.invite_forbidden")
+	}
+
+	email := strings.TrimSpace(req.Email)
+	noTelp := strings.TrimSpace(req.NoTelp)
+	if email == "" && noTelp == "" {
+		return nil, domain.NewValidationError("toko_member.contact_required", nil)
+	}
+
+	invitation := &domain.TokoInvitation{
+		IdToko:    tokoID,
+		Email:     email,
+		NoTelp:    noTelp,
+		Role:      req.Role,
+		Status:    domain.InvitationStatusPending,
+		InvitedBy: userID,
+		ExpiresAt: time.Now().Add(tokoInvitationTTL),
+	}
+	if err := uc.invitationRepo.Create(ctx, invitation); err != nil {
+		return nil, fmt.Errorf("gagal membuat undangan: %w", err)
+	}
+
+	return invitation, nil
+}
+
+func (uc *tokoMemberUsecase) GetTokoInvitations(ctx context.Context, tokoID, userID uint) ([]domain.TokoInvitation, error) {
+	ctx, span := tracing.Start(ctx, "tokoMemberUsecase.GetTokoInvitations")
+	defer span.End()
+
+	if err := uc.authorizeManage(ctx, tokoID, userID); err != nil {
+		return nil, err
+	}
+	return uc.invitationRepo.FindPendingByTokoID(ctx, tokoID)
+}
+
+func (uc *tokoMemberUsecase) ListTokoInvitations(ctx context.Context, tokoID, userID uint, page domain.PageRequest) (*domain.Page[domain.TokoInvitation], error) {
+	ctx, span := tracing.Start(ctx, "tokoMemberUsecase.ListTokoInvitations")
+	defer span.End()
+
+	if err := uc.authorizeManage(ctx, tokoID, userID); err != nil {
+		return nil, err
+	}
+
+	page = normalizePage(page)
+	invitations, info, err := uc.invitationRepo.FindPendingByTokoIDPaged(ctx, tokoID, page)
+	if err != nil {
+		return nil, err
+	}
+	return newPage(page, info, invitations), nil
+}
+
+func (uc *tokoMemberUsecase) CancelInvitation(ctx context.Context, tokoID, invitationID, userID uint) error {
+	ctx, span := tracing.Start(ctx, "tokoMemberUsecase.CancelInvitation")
+	defer span.End()
+
+	if err := uc.authorizeManage(ctx, tokoID, userID); err != nil {
+		return err
+	}
+
+	invitation, err := uc.findPendingInvitation(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.IdToko != tokoID {
+		return domain.NewNotFoundError("toko_member.invitation_not_found")
+	}
+
+	invitation.Status = domain.InvitationStatusCancelled
+	return uc.invitationRepo.Update(ctx, invitation)
+}
+
+func (uc *tokoMemberUsecase) GetMyInvitations(ctx context.Context, userID uint) ([]domain.TokoInvitation, error) {
+	ctx, span := tracing.Start(ctx, "tokoMemberUsecase.GetMyInvitations")
+	defer span.End()
+
+	user, err := uc.userRepo.FindById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.invitationRepo.FindPendingByContact(ctx, user.Email, user.NoTelp)
+}
+
+func (uc *tokoMemberUsecase) ListMyInvitations(ctx context.Context, userID uint, page domain.PageRequest) (*domain.Page[domain.TokoInvitation], error) {
+	ctx, span := tracing.Start(ctx, "tokoMemberUsecase.ListMyInvitations")
+	defer span.End()
+
+	user, err := uc.userRepo.FindById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	page = normalizePage(page)
+	invitations, info, err := uc.invitationRepo.FindPendingByContactPaged(ctx, user.Email, user.NoTelp, page)
+	if err != nil {
+		return nil, err
+	}
+	return newPage(page, info, invitations), nil
+}
+
+func (uc *tokoMemberUsecase) AcceptInvitation(ctx context.Context, invitationID, userID uint) (*domain.TokoMember, error) {
+	ctx, span := tracing.Start(ctx, "tokoMemberUsecase.AcceptInvitation")
+	defer span.End()
+
+	invitation, err := uc.findInvitationForUser(ctx, invitationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var member *domain.TokoMember
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.invitationRepo.MarkAccepted(ctx, invitation.ID, time.Now()); err != nil {
+			return err
+		}
+
+		existing, err := uc.memberRepo.FindByTokoAndUser(ctx, invitation.IdToko, userID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if existing != nil {
+			return domain.NewConflictError("toko_member.already_member")
+		}
+
+		member = &domain.TokoMember{
+			IdToko: invitation.IdToko,
+			IdUser: userID,
+			Role:   invitation.Role,
+		}
+		if err := uc.memberRepo.Create(ctx, member); err != nil {
+			return fmt.Errorf("gagal menambah anggota toko: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	member.Toko = invitation.Toko
+	return member, nil
+}
+
+func (uc *tokoMemberUsecase) DeclineInvitation(ctx context.Context, invitationID, userID uint) error {
+	ctx, span := tracing.Start(ctx, "tokoMemberUsecase.DeclineInvitation")
+	defer span.End()
+
+	invitation, err := uc.findInvitationForUser(ctx, invitationID, userID)
+	if err != nil {
+		return err
+	}
+
+	invitation.Status = domain.InvitationStatusDeclined
+	return uc.invitationRepo.Update(ctx, invitation)
+}
+
+func (uc *tokoMemberUsecase) UpdateMemberRole(ctx context.Context, tokoID, memberUserID uint, req *domain.UpdateTokoMemberRequest, userID uint) (*domain.TokoMember, error) {
+	ctx, span := tracing.Start(ctx, "tokoMemberUsecase.UpdateMemberRole")
+	defer span.End()
+
+	if !domain.IsValidTokoRole(req.Role) {
+		return nil, domain.NewValidationError("toko_member.role_invalid", nil, "role", req.Role)
+	}
+
+	actor, err := uc.actors.Actor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := uc.findMember(ctx, tokoID, memberUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.CanAssignTokoRole(actor, tokoID, member.Role, req.Role) {
+		return nil, domain.NewForbiddenError("toko_member.role_forbidden")
+	}
+
+	member.Role = req.Role
+	if err := uc.memberRepo.Update(ctx, member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func (uc *tokoMemberUsecase) RemoveMember(ctx context.Context, tokoID, memberUserID, userID uint) error {
+	ctx, span := tracing.Start(ctx, "tokoMemberUsecase.RemoveMember")
+	defer span.End()
+
+	member, err := uc.findMember(ctx, tokoID, memberUserID)
+	if err != nil {
+		return err
+	}
+	if member.Role == domain.TokoRoleOwner {
+		return domain.NewForbiddenError("toko_member.owner_not_removable")
+	}
+
+	if memberUserID != userID {
+		actor, err := uc.actors.Actor(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !domain.CanAssignTokoRole(actor, tokoID, member.Role, "") {
+			return domain.NewForbiddenError("toko_member.remove_forbidden")
+		}
+	}
+
+	return uc.memberRepo.Delete(ctx, tokoID, memberUserID)
+}
+
+func (uc *tokoMemberUsecase) findMember(ctx context.Context, tokoID, userID uint) (*domain.TokoMember, error) {
+	member, err := uc.memberRepo.FindByTokoAndUser(ctx, tokoID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NewNotFoundError("toko_member.not_found")
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+func (uc *tokoMemberUsecase) findPendingInvitation(ctx context.Context, invitationID uint) (*domain.TokoInvitation, error) {
+	invitation, err := uc.invitationRepo.FindByID(ctx, invitationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NewNotFoundError("toko_member.invitation_not_found")
+		}
+		return nil, err
+	}
+	if invitation.Status != domain.InvitationStatusPending || time.Now().After(invitation.ExpiresAt) {
+		return nil, domain.NewConflictError("toko_member.invitation_expired")
+	}
+	return invitation, nil
+}
+
+func (uc *tokoMemberUsecase) findInvitationForUser(ctx context.Context, invitationID, userID uint) (*domain.TokoInvitation, error) {
+	invitation, err := uc.findPendingInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	emailMatch := invitation.Email != "" && strings.EqualFold(invitation.Email, user.Email)
+	telpMatch := invitation.NoTelp != "" && invitation.NoTelp == user.NoTelp
+	if !emailMatch && !telpMatch {
+		return nil, domain.NewNotFoundError("toko_member.invitation_not_found")
+	}
+	return invitation, nil
+}
+
+func (uc *tokoMemberUsecase) authorizeManage(ctx context.Context, tokoID, userID uint) error {
+	actor, err := uc.actors.Actor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !domain.CanManageTokoMembers(actor, tokoID) {
+		return domain.NewForbiddenError("toko_member.manage_forbidden")
+	}
+	return nil
+}
@@ -1,45 +1,58 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"gogroceries/domain"
-	"math"
+	"gogroceries/internal/tracing"
 
 	"gorm.io/gorm"
 )
 
 type tokoUsecase struct {
 	tokoRepo domain.TokoRepository
+	actors   domain.ActorProvider
 }
 
-func NewTokoUsecase(sr domain.TokoRepository) domain.TokoUsecase {
+func NewTokoUsecase(sr domain.TokoRepository, ap domain.ActorProvider) domain.TokoUsecase {
 	return &tokoUsecase{
 		tokoRepo: sr,
+		actors:   ap,
 	}
 }
 
-func (uc *tokoUsecase) GetMyToko(userID uint) (*domain.Toko, error) {
-	toko, err := uc.tokoRepo.FindByUserID(userID)
+func (uc *tokoUsecase) GetMyToko(ctx context.Context, userID uint) (*domain.Toko, error) {
+	ctx, span := tracing.Start(ctx, "tokoUsecase.GetMyToko")
+	defer span.End()
+
+	toko, err := uc.tokoRepo.FindByUserID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("toko tidak ditemukan untuk user ini")
+			return nil, domain.NewNotFoundError("toko.not_found_for_user")
 		}
 		return nil, err
 	}
 	return toko, nil
 }
 
-func (uc *tokoUsecase) UpdateToko(id uint, req *domain.UpdateTokoRequest, userID uint) (*domain.Toko, error) {
-	toko, err := uc.tokoRepo.FindByUserID(userID) 
+func (uc *tokoUsecase) UpdateToko(ctx context.Context, id uint, req *domain.UpdateTokoRequest, userID uint) (*domain.Toko, error) {
+	ctx, span := tracing.Start(ctx, "tokoUsecase.UpdateToko")
+	defer span.End()
+
+	toko, err := uc.tokoRepo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("toko tidak ditemukan")
+			return nil, domain.NewNotFoundError("toko.not_found")
 		}
 		return nil, err
 	}
 
-	if toko.ID != id {
-		return nil, errors.New("forbidden: anda tidak bisa mengubah toko ini")
+	actor, err := uc.actors.Actor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.CanEditToko(actor, toko) {
+		return nil, domain.NewForbiddenError("toko.update_forbidden")
 	}
 
 	if req.NamaToko != "" {
@@ -49,47 +62,34 @@ func (uc *tokoUsecase) UpdateToko(id uint, req *domain.UpdateTokoRequest, userID
 		toko.UrlFoto = req.UrlFoto
 	}
 
-	err = uc.tokoRepo.Update(toko)
+	err = uc.tokoRepo.Update(ctx, toko)
 	if err != nil {
 		return nil, err
 	}
 	return toko, nil
 }
 
-func (uc *tokoUsecase) GetAllTokos(filter domain.TokoFilter, page, limit int) ([]domain.Toko, *domain.PaginationResponse, error) {
-	if page <= 0 {
-		page = 1
-	}
-	if limit <= 0 {
-		limit = 10 
-	}
-	offset := (page - 1) * limit
-	
-	tokos, totalData, err := uc.tokoRepo.FindAll(filter, offset, limit)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	totalPages := 0
-	if totalData > 0 {
-		totalPages = int(math.Ceil(float64(totalData) / float64(limit)))
-	}
+func (uc *tokoUsecase) GetAllTokos(ctx context.Context, filter domain.TokoFilter, page domain.PageRequest) (*domain.Page[domain.Toko], error) {
+	ctx, span := tracing.Start(ctx, "tokoUsecase.GetAllTokos")
+	defer span.End()
 
-	pagination := &domain.PaginationResponse{
-		Page:      page,
-		Limit:     limit,
-		TotalData: int(totalData),
-		TotalPage: totalPages,
+	page = normalizePage(page)
+	tokos, info, err := uc.tokoRepo.FindAll(ctx, filter, page)
+	if err != nil {
+		return nil, err
 	}
 
-	return tokos, pagination, err
+	return newPage(page, info, tokos), nil
 }
 
-func (uc *tokoUsecase) GetTokoByID(id uint) (*domain.Toko, error) {
-    toko, err := uc.tokoRepo.FindByID(id) 
+func (uc *tokoUsecase) GetTokoByID(ctx context.Context, id uint) (*domain.Toko, error) {
+	ctx, span := tracing.Start(ctx, "tokoUsecase.GetTokoByID")
+	defer span.End()
+
+    toko, err := uc.tokoRepo.FindByID(ctx, id) 
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, errors.New("toko tidak ditemukan")
+				return nil, domain.NewNotFoundError("toko.not_found")
 			}
 			return nil, err
 		}
@@ -1,13 +1,16 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"gogroceries/domain"          
 	"gogroceries/internal/helper" 
-	"log"
-	"math"
+	"gogroceries/internal/logger"
+	"gogroceries/internal/metrics"
+	"gogroceries/internal/tracing"
 
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/gorm"
 )
 
@@ -17,6 +20,8 @@ type trxUsecase struct {
 	alamatRepo  domain.AlamatRepository 
 	categoryRepo domain.CategoryRepository 
 	tokoRepo    domain.TokoRepository    
+	actors       domain.ActorProvider
+	txManager    domain.TxManager
 }
 
 func NewTrxUsecase(
@@ -25,6 +30,8 @@ func NewTrxUsecase(
     ar domain.AlamatRepository,
     cr domain.CategoryRepository,
     trRepo domain.TokoRepository,
+    ap domain.ActorProvider,
+    txm domain.TxManager,
 ) domain.TrxUsecase {
     return &trxUsecase{
         trxRepo:      tr,
@@ -32,18 +39,60 @@ func NewTrxUsecase(
         alamatRepo:   ar,
         categoryRepo: cr,
         tokoRepo:     trRepo,
+        actors:       ap,
+        txManager:    txm,
     }
 }
 
-func (uc *trxUsecase) CreateTransaksi(req *domain.CreateTransaksiRequest, userID uint) (*domain.Trx, error) {
-	alamat, err := uc.alamatRepo.FindByIDAndUserID(req.IdAlamatKirim, userID)
+func (uc *trxUsecase) CreateTransaksi(ctx context.Context, req *domain.CreateTransaksiRequest, userID uint) (*domain.Trx, error) {
+	ctx, span := tracing.Start(ctx, "trxUsecase.CreateTransaksi")
+	defer span.End()
+
+	trx, err := uc.createTransaksi(ctx, req, userID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("alamat pengiriman tidak valid atau bukan milik anda")
-		}
+		reason := checkoutFailureReason(err)
+		metrics.CheckoutFailures.WithLabelValues(reason).Inc()
+		span.SetAttributes(attribute.String("checkout.failure_reason", reason))
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int64("trx.id", int64(trx.ID)), attribute.Int("trx.items", len(req.DetailTrx)))
+
+	metrics.OrdersCreated.Inc()
+	metrics.Revenue.Add(float64(trx.HargaTotal))
+	for _, item := range req.DetailTrx {
+		metrics.OrderItems.Add(float64(item.Kuantitas))
+	}
+	return trx, nil
+}
+
+// checkoutFailureReason maps the errors createTransaksi returns to a metrics label.
+func checkoutFailureReason(err error) string {
+	switch domain.ErrorCodeOf(err) {
+	case domain.CodeValidation:
+		return metrics.ReasonInvalidAddress
+	case domain.CodeNotFound:
+		return metrics.ReasonProductNotFound
+	case domain.CodeInsufficientStock:
+		return metrics.ReasonInsufficientStock
+	default:
+		return metrics.ReasonInternal
+	}
+}
+
+func (uc *trxUsecase) createTransaksi(ctx context.Context, req *domain.CreateTransaksiRequest, userID uint) (*domain.Trx, error) {
+	alamat, err := uc.alamatRepo.FindByID(ctx, req.IdAlamatKirim)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, fmt.Errorf("gagal validasi alamat: %w", err)
 	}
 
+	actor, actorErr := uc.actors.Actor(ctx, userID)
+	if actorErr != nil {
+		return nil, actorErr
+	}
+	if err != nil || !domain.CanManageAlamat(actor, alamat) {
+		return nil, domain.NewValidationError("trx.alamat_invalid", nil)
+	}
+
 	var detailsToSave []domain.DetailTrx
 	var logsToSave []domain.LogProduk
 	var totalHargaKeseluruhan int = 0
@@ -53,7 +102,7 @@ func (uc *trxUsecase) CreateTransaksi(req *domain.CreateTransaksiRequest, userID
 		productIDs[i] = item.IdProduk
 	}
 
-	produks, err := uc.produkRepo.FindByIDs(productIDs) 
+	produks, err := uc.produkRepo.FindByIDs(ctx, productIDs) 
 	if err != nil {
 		return nil, fmt.Errorf("gagal mengambil data produk: %w", err)
 	}
@@ -66,15 +115,15 @@ func (uc *trxUsecase) CreateTransaksi(req *domain.CreateTransaksiRequest, userID
 	for _, item := range req.DetailTrx {
 		produk, ok := produkMap[item.IdProduk]
 		if !ok {
-			return nil, fmt.Errorf("produk dengan ID %d tidak ditemukan", item.IdProduk)
+			return nil, domain.NewNotFoundError("trx.produk_not_found", "id", item.IdProduk)
 		}
 
 		if produk.Stok < item.Kuantitas {
-			return nil, fmt.Errorf("stok produk '%s' tidak mencukupi (tersedia: %d, diminta: %d)", produk.NamaProduk, produk.Stok, item.Kuantitas)
+			return nil, domain.NewInsufficientStockError("trx.insufficient_stock", "produk", produk.NamaProduk, "stok", produk.Stok, "kuantitas", item.Kuantitas)
 		}
 
 		if produk.Toko == nil || produk.Category == nil {
-			log.Printf("Fatal Error: Gagal preload Toko/Category untuk produk ID %d", item.IdProduk)
+			logger.FromContext(ctx).Error("produk loaded without toko/category", "produk_id", item.IdProduk)
 			return nil, fmt.Errorf("gagal mendapatkan detail toko/kategori untuk produk ID %d", item.IdProduk)
 		}
 
@@ -111,10 +160,19 @@ func (uc *trxUsecase) CreateTransaksi(req *domain.CreateTransaksiRequest, userID
 		HargaTotal:    totalHargaKeseluruhan,
 		KodeInvoice:   kodeInvoice,
 		MethodBayar:   req.MethodBayar,
-		Status:        "pending",
+		Status:        domain.TrxStatusPending,
 	}
 
-	createdTrx, err := uc.trxRepo.Create(newTrx, detailsToSave, logsToSave)
+	var createdTrx *domain.Trx
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		for _, detail := range detailsToSave {
+			if err := uc.produkRepo.UpdateStok(ctx, detail.IdProduk, -detail.Kuantitas); err != nil {
+				return err
+			}
+		}
+		createdTrx, err = uc.trxRepo.Create(ctx, newTrx, detailsToSave, logsToSave)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -123,37 +181,202 @@ func (uc *trxUsecase) CreateTransaksi(req *domain.CreateTransaksiRequest, userID
 }
 
 
-func (uc *trxUsecase) GetAllTransaksiUser(userID uint, filter domain.TrxFilter, page, limit int) ([]domain.Trx, *domain.PaginationResponse, error) {
-    if page < 1 {
-        page = 1
-    }
-    if limit < 1 {
-        limit = 10 
-    }
-    offset := (page - 1) * limit
+func (uc *trxUsecase) GetAllTransaksiUser(ctx context.Context, userID uint, filter domain.TrxFilter, page domain.PageRequest) (*domain.Page[domain.Trx], error) {
+	ctx, span := tracing.Start(ctx, "trxUsecase.GetAllTransaksiUser")
+	defer span.End()
 
-    trxs, totalData, err := uc.trxRepo.FindAllByUserID(userID, filter, limit, offset)
-    if err != nil {
-        return nil, nil, err
-    }
+	page = normalizePage(page)
+	trxs, info, err := uc.trxRepo.FindAllByUserID(ctx, userID, filter, page)
+	if err != nil {
+		return nil, err
+	}
 
-    totalPage := int(math.Ceil(float64(totalData) / float64(limit)))
-    
-    pagination := &domain.PaginationResponse{
-        Page:      page,
-        Limit:     limit,
-        TotalData: int(totalData),
-        TotalPage: totalPage,
-        Data:      trxs,
-    }
+	return newPage(page, info, trxs), nil
+}
+
+func (uc *trxUsecase) GetTransaksiByID(ctx context.Context, id uint, userID uint) (*domain.Trx, error) {
+	ctx, span := tracing.Start(ctx, "trxUsecase.GetTransaksiByID")
+	defer span.End()
+
+	trx, err := uc.trxRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NewNotFoundError("trx.not_found")
+		}
+		return nil, err
+	}
+
+	actor, err := uc.actors.Actor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.CanViewTrx(actor, trx) {
+		return nil, domain.NewNotFoundError("trx.not_found")
+	}
+	return trx, nil
+}
+
+func (uc *trxUsecase) GetAllTransaksiToko(ctx context.Context, tokoID uint, userID uint, filter domain.TrxFilter, page domain.PageRequest) (*domain.Page[domain.Trx], error) {
+	ctx, span := tracing.Start(ctx, "trxUsecase.GetAllTransaksiToko")
+	defer span.End()
+
+	actor, err := uc.actors.Actor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.CanViewTokoOrders(actor, tokoID) {
+		return nil, domain.NewForbiddenError("trx.toko_forbidden")
+	}
+
+	page = normalizePage(page)
+	trxs, info, err := uc.trxRepo.FindAllByTokoID(ctx, tokoID, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(page, info, trxs), nil
+}
+
+func (uc *trxUsecase) GetTransaksiTokoByID(ctx context.Context, id uint, tokoID uint, userID uint) (*domain.Trx, error) {
+	ctx, span := tracing.Start(ctx, "trxUsecase.GetTransaksiTokoByID")
+	defer span.End()
+
+	actor, err := uc.actors.Actor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.CanViewTokoOrders(actor, tokoID) {
+		return nil, domain.NewForbiddenError("trx.toko_forbidden")
+	}
+
+	trx, err := uc.findTokoTrx(ctx, id, tokoID)
+	if err != nil {
+		return nil, err
+	}
 
-    return trxs, pagination, nil
+	tokoDetails := make([]domain.DetailTrx, 0, len(trx.DetailTrx))
+	for _, d := range trx.DetailTrx {
+		if d.IdToko == tokoID {
+			tokoDetails = append(tokoDetails, d)
+		}
+	}
+	trx.DetailTrx = tokoDetails
+
+	return trx, nil
+}
+
+func (uc *trxUsecase) UpdateStatusTransaksiToko(ctx context.Context, id uint, tokoID uint, req *domain.UpdateTrxStatusRequest, userID uint) (*domain.Trx, error) {
+	ctx, span := tracing.Start(ctx, "trxUsecase.UpdateStatusTransaksiToko")
+	defer span.End()
+
+	actor, err := uc.actors.Actor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.CanViewTokoOrders(actor, tokoID) {
+		return nil, domain.NewForbiddenError("trx.toko_forbidden")
+	}
+
+	trx, err := uc.findTokoTrx(ctx, id, tokoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !domain.CanUpdateTokoOrder(actor, tokoID, trx) {
+		return nil, domain.NewForbiddenError("trx.update_forbidden")
+	}
+
+	if err := uc.changeStatus(ctx, trx, req.Status); err != nil {
+		return nil, err
+	}
+	return trx, nil
+}
+
+func (uc *trxUsecase) GetAllTransaksi(ctx context.Context, userID uint, filter domain.TrxFilter, page domain.PageRequest) (*domain.Page[domain.Trx], error) {
+	ctx, span := tracing.Start(ctx, "trxUsecase.GetAllTransaksi")
+	defer span.End()
+
+	actor, err := uc.actors.Actor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.CanListAllOrders(actor) {
+		return nil, domain.NewForbiddenError("trx.list_forbidden")
+	}
+
+	page = normalizePage(page)
+	trxs, info, err := uc.trxRepo.FindAll(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(page, info, trxs), nil
 }
 
-func (uc *trxUsecase) GetTransaksiByID(id uint, userID uint) (*domain.Trx, error) {
-	trx, err := uc.trxRepo.FindByID(id, userID) 
+// UpdateStatusTransaksi changes the status of any order for platform staff,
+// such as an admin cancelling an order or a courier confirming delivery.
+func (uc *trxUsecase) UpdateStatusTransaksi(ctx context.Context, id uint, req *domain.UpdateTrxStatusRequest, userID uint) (*domain.Trx, error) {
+	ctx, span := tracing.Start(ctx, "trxUsecase.UpdateStatusTransaksi")
+	defer span.End()
+
+	trx, err := uc.trxRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NewNotFoundError("trx.not_found")
+		}
+		return nil, err
+	}
+
+	actor, err := uc.actors.Actor(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
+	if !domain.CanUpdateOrderStatus(actor, trx, req.Status) {
+		return nil, domain.NewForbiddenError("trx.update_forbidden")
+	}
+
+	if err := uc.changeStatus(ctx, trx, req.Status); err != nil {
+		return nil, err
+	}
 	return trx, nil
-}
\ No newline at end of file
+}
+
+// changeStatus moves trx to status and puts the stock back when the order is
+// cancelled, in one transaction.
+func (uc *trxUsecase) changeStatus(ctx context.Context, trx *domain.Trx, status string) error {
+	if !domain.CanTransitionTrxStatus(trx.Status, status) {
+		return domain.NewConflictError("trx.status_transition", "from", trx.Status, "to", status)
+	}
+
+	err := uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.trxRepo.UpdateStatus(ctx, trx.ID, trx.Status, status); err != nil {
+			return err
+		}
+		if status != domain.TrxStatusCancelled {
+			return nil
+		}
+		for _, detail := range trx.DetailTrx {
+			if err := uc.produkRepo.UpdateStok(ctx, detail.IdProduk, detail.Kuantitas); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	trx.Status = status
+	metrics.OrderStatusChanges.WithLabelValues(status).Inc()
+	return nil
+}
+
+func (uc *trxUsecase) findTokoTrx(ctx context.Context, id uint, tokoID uint) (*domain.Trx, error) {
+	trx, err := uc.trxRepo.FindByIDAndTokoID(ctx, id, tokoID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NewNotFoundError("trx.not_found")
+		}
+		return nil, err
+	}
+	return trx, nil
+}
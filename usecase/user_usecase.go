@@ -1,34 +1,67 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"gogroceries/domain"
 	"gogroceries/internal/helper"
+	"gogroceries/internal/tracing"
+	"time"
+
 	"gorm.io/gorm"
 )
 
 type userUsecase struct {
-	userRepo domain.UserRepository
+	userRepo     domain.UserRepository
+	accountRepo  domain.AccountRepository
+	identityRepo domain.UserIdentityRepository
+	userRoleRepo domain.UserRoleRepository
 }
 
-func NewUserUsecase(userRepo domain.UserRepository) domain.UserUsecase {
+func NewUserUsecase(userRepo domain.UserRepository, accountRepo domain.AccountRepository, identityRepo domain.UserIdentityRepository, userRoleRepo domain.UserRoleRepository) domain.UserUsecase {
 	return &userUsecase{
-		userRepo: userRepo,
+		userRepo:     userRepo,
+		accountRepo:  accountRepo,
+		identityRepo: identityRepo,
+		userRoleRepo: userRoleRepo,
 	}
 }
 
-func (uc *userUsecase) GetProfileById(id uint) (*domain.User, error) {
-	user, err := uc.userRepo.FindById(id)
+func (uc *userUsecase) GetProfileById(ctx context.Context, id uint) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "userUsecase.GetProfileById")
+	defer span.End()
+
+	user, err := uc.userRepo.FindById(ctx, id)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NewNotFoundError("user.not_found")
+		}
 		return nil, err
 	}
 	return user, nil
 }
 
-func (uc *userUsecase) UpdateProfile(id uint, req *domain.UpdateProfileRequest) (*domain.User, error) {
-	existingUser, err := uc.userRepo.FindById(id)
+func (uc *userUsecase) GetRoles(ctx context.Context, user *domain.User) ([]domain.Role, error) {
+	ctx, span := tracing.Start(ctx, "userUsecase.GetRoles")
+	defer span.End()
+
+	roles, err := loadUserRoles(ctx, uc.userRoleRepo, user)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat role user: %w", err)
+	}
+	return roles, nil
+}
+
+func (uc *userUsecase) UpdateProfile(ctx context.Context, id uint, req *domain.UpdateProfileRequest) (*domain.User, error) {
+	ctx, span := tracing.Start(ctx, "userUsecase.UpdateProfile")
+	defer span.End()
+
+	existingUser, err := uc.userRepo.FindById(ctx, id)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NewNotFoundError("user.not_found")
+		}
 		return nil, err
 	}
 
@@ -55,9 +88,9 @@ func (uc *userUsecase) UpdateProfile(id uint, req *domain.UpdateProfileRequest)
 	}
 
 	if req.Email != nil && *req.Email != existingUser.Email {
-		_, err := uc.userRepo.FindByEmail(*req.Email)
+		_, err := uc.userRepo.FindByEmail(ctx, *req.Email)
 		if err == nil {
-			return nil, errors.New("email sudah terdaftar")
+			return nil, domain.NewConflictError("user.email_taken")
 		}
 		if !errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("gagal cek email: %w", err)
@@ -66,9 +99,9 @@ func (uc *userUsecase) UpdateProfile(id uint, req *domain.UpdateProfileRequest)
 	}
 
 	if req.NoTelp != nil && *req.NoTelp != existingUser.NoTelp {
-		_, err := uc.userRepo.FindByNoTelp(*req.NoTelp)
+		_, err := uc.userRepo.FindByNoTelp(ctx, *req.NoTelp)
 		if err == nil {
-			return nil, errors.New("nomor telepon sudah terdaftar")
+			return nil, domain.NewConflictError("user.phone_taken")
 		}
 		if !errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("gagal cek no telp: %w", err)
@@ -82,19 +115,60 @@ func (uc *userUsecase) UpdateProfile(id uint, req *domain.UpdateProfileRequest)
 			return nil, errors.New("gagal hash password baru")
 		}
 		existingUser.KataSandi = hashedPassword
+		existingUser.NoPassword = false
 	}
 
-	if err := uc.userRepo.Update(existingUser); err != nil {
+	if err := uc.userRepo.Update(ctx, existingUser); err != nil {
 		return nil, err
 	}
 
 	return existingUser, nil
 }
 
-func (uc *userUsecase) DeleteProfile(id uint) error {
-	user, err := uc.userRepo.FindById(id)
+func (uc *userUsecase) DeleteProfile(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "userUsecase.DeleteProfile")
+	defer span.End()
+
+	if _, err := uc.userRepo.FindById(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.NewNotFoundError("user.not_found")
+		}
+		return err
+	}
+	return uc.accountRepo.Anonymize(ctx, id, time.Now())
+}
+
+func (uc *userUsecase) ExportData(ctx context.Context, id uint) (*domain.UserDataExport, error) {
+	ctx, span := tracing.Start(ctx, "userUsecase.ExportData")
+	defer span.End()
+
+	export, err := uc.accountRepo.ExportData(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NewNotFoundError("user.not_found")
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+func (uc *userUsecase) DeleteAccount(ctx context.Context, id uint, req *domain.DeleteAccountRequest) error {
+	ctx, span := tracing.Start(ctx, "userUsecase.DeleteAccount")
+	defer span.End()
+
+	user, err := uc.userRepo.FindById(ctx, id)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.NewNotFoundError("user.not_found")
+		}
 		return err
 	}
-	return uc.userRepo.Delete(user)
+
+	// Accounts created through social login never chose a password. Linking a
+	// provider to a password account does not waive the check.
+	if !user.NoPassword && !helper.CheckPasswordHash(req.KataSandi, user.KataSandi) {
+		return domain.NewUnauthorizedError("user.wrong_password")
+	}
+
+	return uc.accountRepo.Anonymize(ctx, id, time.Now())
 }
\ No newline at end of file
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"gogroceries/domain"
+	"gogroceries/internal/helper"
+	"testing"
+	"time"
+)
+
+type memAccountRepo struct {
+	domain.AccountRepository
+	anonymized []uint
+}
+
+func (r *memAccountRepo) Anonymize(ctx context.Context, userID uint, at time.Time) error {
+	r.anonymized = append(r.anonymized, userID)
+	return nil
+}
+
+func TestDeleteAccountPassword(t *testing.T) {
+	hash, err := helper.HashPassword("rahasia123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		noPassword bool
+		linked     bool
+		kataSandi  string
+		wantErr    domain.ErrorCode
+	}{
+		{"password account, right password", false, false, "rahasia123", ""},
+		{"password account, wrong password", false, false, "salah", domain.CodeUnauthorized},
+		{"password account with social login, no password", false, true, "", domain.CodeUnauthorized},
+		{"password account with social login, right password", false, true, "rahasia123", ""},
+		{"social login account", true, true, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &memUserRepo{users: map[uint]*domain.User{}}
+			identities := &memIdentityRepo{}
+			accounts := &memAccountRepo{}
+			user := &domain.User{Email: "udin@example.com", KataSandi: hash, NoPassword: tt.noPassword}
+			users.Create(context.Background(), user)
+			if tt.linked {
+				identities.Create(context.Background(), &domain.UserIdentity{IdUser: user.ID, Provider: "google", Subject: "sub-udin"})
+			}
+
+			uc := NewUserUsecase(users, accounts, identities, &memUserRoleRepo{})
+			err := uc.DeleteAccount(context.Background(), user.ID, &domain.DeleteAccountRequest{KataSandi: tt.kataSandi})
+
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("DeleteAccount() error = %v", err)
+			}
+			if tt.wantErr != "" && domain.ErrorCodeOf(err) != tt.wantErr {
+				t.Fatalf("DeleteAccount() error = %v, want code %s", err, tt.wantErr)
+			}
+			if deleted := len(accounts.anonymized) == 1; deleted != (tt.wantErr == "") {
+				t.Errorf("account anonymized = %v", deleted)
+			}
+		})
+	}
+}